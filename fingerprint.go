@@ -0,0 +1,91 @@
+package spectagular
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Fingerprint returns a stable hash of the parsed struct tags for a type (adding them to the cache
+// if needed). The hash is built from the name, index path, and value of every FieldTag so it can be
+// used to detect when a type's struct tags have changed between builds.
+func (t *StructTagCache[T]) Fingerprint(rType reflect.Type) (uint64, error) {
+	tags, err := t.GetOrAdd(rType)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	for _, tag := range tags {
+		fmt.Fprintf(h, "%s:%v=", tag.FieldName, tag.FieldIndexPath)
+		writeFingerprint(h, reflect.ValueOf(tag.Value))
+		io.WriteString(h, ";")
+	}
+	return h.Sum64(), nil
+}
+
+// writeFingerprint writes a deterministic encoding of a value. Pointers are followed and map keys
+// are sorted so that the encoding does not depend on addresses or iteration order.
+func writeFingerprint(w io.Writer, v reflect.Value) {
+	if !v.IsValid() {
+		io.WriteString(w, "nil")
+		return
+	}
+	if v.CanInterface() {
+		if s, ok := v.Interface().(fmt.Stringer); ok && (v.Kind() != reflect.Pointer || !v.IsNil()) {
+			fmt.Fprintf(w, "%q", s.String())
+			return
+		}
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			io.WriteString(w, "nil")
+			return
+		}
+		writeFingerprint(w, v.Elem())
+	case reflect.Struct:
+		io.WriteString(w, "{")
+		for i := 0; i < v.NumField(); i++ {
+			io.WriteString(w, v.Type().Field(i).Name+":")
+			writeFingerprint(w, v.Field(i))
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "}")
+	case reflect.Slice, reflect.Array:
+		io.WriteString(w, "[")
+		for i := 0; i < v.Len(); i++ {
+			writeFingerprint(w, v.Index(i))
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "]")
+	case reflect.Map:
+		pairs := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var pair strings.Builder
+			writeFingerprint(&pair, iter.Key())
+			pair.WriteString(":")
+			writeFingerprint(&pair, iter.Value())
+			pairs = append(pairs, pair.String())
+		}
+		sort.Strings(pairs)
+		fmt.Fprintf(w, "map%s", pairs)
+	case reflect.Bool:
+		fmt.Fprint(w, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fmt.Fprint(w, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		fmt.Fprint(w, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		fmt.Fprint(w, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		fmt.Fprint(w, v.Complex())
+	case reflect.String:
+		fmt.Fprintf(w, "%q", v.String())
+	default:
+		io.WriteString(w, v.Type().String())
+	}
+}
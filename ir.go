@@ -0,0 +1,137 @@
+package spectagular
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// IR is a self describing representation of the parsed struct tags in a cache that does not
+// depend on the original types, so that it can be shared between processes (i.e. a code
+// generation step and a runtime binary) and queried by type name for read only metadata.
+type IR struct {
+	// Tag is the name of the struct tag that was parsed (i.e. json).
+	Tag string `json:"tag"`
+	// Options are the names of the options in the definition struct in the order they are defined.
+	Options []string `json:"options"`
+	// Types are the parsed types keyed by their package path qualified name (i.e.
+	// github.com/user/pkg.Type).
+	Types map[string]IRType `json:"types"`
+}
+
+// IRType is the representation of the parsed struct tags of a single type.
+type IRType struct {
+	Fields []IRField `json:"fields"`
+}
+
+// IRField is the representation of the parsed struct tags of a single field.
+type IRField struct {
+	// Name is the name of the field.
+	Name string `json:"name"`
+	// Index is the index sequence of the field (as used by reflect.Value.FieldByIndex).
+	Index []int `json:"index"`
+	// Type is the name of the type of the field (i.e. []string).
+	Type string `json:"type"`
+	// Values are the parsed values of the options of the field keyed by option name. Scalar values
+	// are formatted as they would be written in a struct tag without quotes, lists and maps are
+	// written as `[a,b]` and `{k=v}`, and options with nil values are left out.
+	Values map[string]string `json:"values"`
+}
+
+// Lookup returns the representation of a type by its package path qualified name.
+func (ir *IR) Lookup(typeName string) (IRType, bool) {
+	irType, ok := ir.Types[typeName]
+	return irType, ok
+}
+
+// Field returns the representation of a field of a type by name.
+func (t IRType) Field(name string) (IRField, bool) {
+	for _, field := range t.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return IRField{}, false
+}
+
+// IR returns the representation of every type in the cache.
+func (t *StructTagCache[T]) IR() (*IR, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	ir := &IR{
+		Tag:     t.tagName,
+		Options: append([]string{}, t.names...),
+		Types:   make(map[string]IRType, len(t.typeToTags)),
+	}
+	for rType, tags := range t.typeToTags {
+		fields := make([]IRField, len(tags))
+		for i, tag := range tags {
+			values, err := t.irValues(tag.Value)
+			if err != nil {
+				return nil, fmt.Errorf("unable to build IR for field '%s' of type '%s': %w", tag.FieldName, typeName(rType), err)
+			}
+			fields[i] = IRField{
+				Name:   tag.FieldName,
+				Index:  append([]int{}, tag.FieldIndexPath...),
+				Type:   tag.FieldType.String(),
+				Values: values,
+			}
+		}
+		ir.Types[typeName(rType)] = IRType{Fields: fields}
+	}
+	return ir, nil
+}
+
+// irValues formats the value of every option of a parsed value.
+func (t *StructTagCache[T]) irValues(v T) (map[string]string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	values := make(map[string]string, len(t.names))
+	if !rv.IsValid() {
+		return values, nil
+	}
+	for _, name := range t.names {
+		opts := t.structTagMap[name]
+		if len(opts) == 0 {
+			continue
+		}
+		fv := rv.FieldByIndex(opts[0].fieldIndex())
+		switch {
+		case (fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface) && fv.IsNil():
+			continue
+		case fv.Kind() == reflect.Bool:
+			values[name] = strconv.FormatBool(fv.Bool())
+		case opts[0].FlagSet != EmptyTag || fv.Kind() == reflect.Map || (fv.Kind() == reflect.Slice && fv.Type() != reflect.TypeOf([]byte(nil))):
+			value, err := t.marshalOption(opts[0], fv)
+			if err != nil {
+				return nil, fmt.Errorf("unable to format option '%s': %w", name, err)
+			}
+			values[name] = value
+		default:
+			values[name] = formatTagValue(fv, opts[0].Layout)
+		}
+	}
+	return values, nil
+}
+
+// MarshalIR returns the JSON encoding of the representation of every type in the cache. The
+// encoding is stable (types and values are sorted by name) so it can be compared between builds.
+func (t *StructTagCache[T]) MarshalIR() ([]byte, error) {
+	ir, err := t.IR()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(ir)
+}
+
+// UnmarshalIR parses the JSON encoding of an IR returned by MarshalIR.
+func UnmarshalIR(data []byte) (*IR, error) {
+	ir := &IR{}
+	if err := json.Unmarshal(data, ir); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal IR: %w", err)
+	}
+	if ir.Types == nil {
+		ir.Types = make(map[string]IRType)
+	}
+	return ir, nil
+}
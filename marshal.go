@@ -0,0 +1,227 @@
+package spectagular
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MarshalTagValue is the reverse of parsing a struct tag. It builds a tag string from a value of
+// type T that parses back into an equivalent value. Options are written in the order they are
+// declared in T as `key=value` pairs with the $name option first (without a key), bool options as
+// just their key (or `!key` for defaulttrue options that are false), slices as `[a,b,c]`, and
+// maps as `{k=v,...}` (sorted by key unless they are an OrderedMap). Options with zero values
+// (other than $name) are left out unless they have a default and values that contain the
+// separator, quotes, spaces, brackets, or '=' are quoted. If T has a $tail option, every other
+// option must be positional since anything after the positional options is part of the tail.
+func (t *StructTagCache[T]) MarshalTagValue(v T) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return EmptyTag, nil
+		}
+		rv = rv.Elem()
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	if t.hasTail {
+		return t.marshalTail(rv)
+	}
+	parts := make([]string, 0, len(t.names))
+	for _, name := range t.names {
+		opts := t.structTagMap[name]
+		if len(opts) == 0 {
+			continue
+		}
+		st := opts[0]
+		fv := rv.FieldByIndex(st.fieldIndex())
+		defaulted := t.hasDefault(rv, st)
+		if fv.Kind() == reflect.Bool {
+			switch {
+			case fv.Bool() && !st.DefaultTrue:
+				parts = append(parts, name)
+			case !fv.Bool() && (st.DefaultTrue || defaulted):
+				parts = append(parts, "!"+name)
+			}
+			continue
+		}
+		if fv.IsZero() && name != NameTag && !defaulted {
+			continue
+		}
+		value, err := t.marshalOption(st, fv)
+		if err != nil {
+			return EmptyTag, fmt.Errorf("unable to marshal option '%s': %w", name, err)
+		}
+		if name == NameTag {
+			parts = append([]string{value}, parts...)
+		} else {
+			parts = append(parts, name+"="+value)
+		}
+	}
+	return strings.Join(parts, t.options.separator), nil
+}
+
+// marshalTail builds a tag for a value of a T that has a $tail option. Positional options are
+// written (without keys) in the order of their positions followed by the tail as is. An error is
+// returned if any other option would need to be written.
+func (t *StructTagCache[T]) marshalTail(rv reflect.Value) (string, error) {
+	parts := make([]string, t.tailStart)
+	tail := EmptyTag
+	for _, name := range t.names {
+		opts := t.structTagMap[name]
+		if len(opts) == 0 {
+			continue
+		}
+		st := opts[0]
+		fv := rv.FieldByIndex(st.fieldIndex())
+		switch {
+		case name == TailTag:
+			tail = fv.String()
+		case st.Positional:
+			value, err := t.marshalOption(st, fv)
+			if err != nil {
+				return EmptyTag, fmt.Errorf("unable to marshal option '%s': %w", name, err)
+			}
+			parts[st.Position] = value
+		case !fv.IsZero() || st.DefaultTrue || t.hasDefault(rv, st):
+			return EmptyTag, fmt.Errorf("unable to marshal option '%s': only positional options can be written before %s", name, TailTag)
+		}
+	}
+	if tail != EmptyTag {
+		return strings.Join(append(parts, tail), t.options.separator), nil
+	}
+	for len(parts) > 0 && parts[len(parts)-1] == EmptyTag {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, t.options.separator), nil
+}
+
+// hasDefault returns whether or not an option would be given a default if it was left out of a tag,
+// in which case its zero value needs to be written. nil pointers are never written since they can
+// not be given in a tag.
+func (t *StructTagCache[T]) hasDefault(rv reflect.Value, st StructTagOption) bool {
+	if kind := rv.FieldByIndex(st.fieldIndex()).Kind(); kind == reflect.Pointer || kind == reflect.Interface {
+		return false
+	}
+	if st.Default != EmptyTag {
+		return true
+	}
+	if provider, ok := reflect.New(rv.Type()).Interface().(DefaultProvider); ok {
+		_, ok = provider.DefaultFor(st.Name)
+		return ok
+	}
+	return false
+}
+
+// marshalOption formats the value of a single option so that it can be given to the option in a
+// struct tag.
+func (t *StructTagCache[T]) marshalOption(st StructTagOption, fv reflect.Value) (string, error) {
+	if st.FlagSet != EmptyTag {
+		flags, err := marshalFlags(t.options.flagSets[st.FlagSet], fv.Int())
+		return quoteTagValue(flags, t.options.separator), err
+	}
+	if fv.Kind() == reflect.Slice && fv.Type() != reflect.TypeOf([]byte(nil)) {
+		values := make([]string, fv.Len())
+		for i := range values {
+			values[i] = quoteTagValue(formatTagValue(fv.Index(i), st.Layout), t.options.separator)
+		}
+		return "[" + strings.Join(values, t.options.separator) + "]", nil
+	}
+	if ordered := reflect.Indirect(fv); ordered.Kind() == reflect.Struct && ordered.Type().Implements(reflect.TypeOf((*orderedMap)(nil)).Elem()) {
+		keys, values := ordered.FieldByName("Keys"), ordered.FieldByName("Values")
+		pairs := make([]string, 0, keys.Len())
+		for i := 0; i < keys.Len(); i++ {
+			if value := values.MapIndex(keys.Index(i)); value.IsValid() {
+				pairs = append(pairs, formatTagValue(keys.Index(i), EmptyTag)+"="+quoteTagValue(formatTagValue(value, st.Layout), t.options.separator))
+			}
+		}
+		return "{" + strings.Join(pairs, t.options.separator) + "}", nil
+	}
+	if fv.Kind() == reflect.Map {
+		pairs := make([]string, 0, fv.Len())
+		iter := fv.MapRange()
+		for iter.Next() {
+			pairs = append(pairs, formatTagValue(iter.Key(), EmptyTag)+"="+quoteTagValue(formatTagValue(iter.Value(), st.Layout), t.options.separator))
+		}
+		sort.Strings(pairs)
+		return "{" + strings.Join(pairs, t.options.separator) + "}", nil
+	}
+	return quoteTagValue(formatTagValue(fv, st.Layout), t.options.separator), nil
+}
+
+// formatTagValue formats a value the same way it would be written in a struct tag. Times are
+// formatted with layout if it is set.
+func formatTagValue(v reflect.Value, layout string) string {
+	switch value := v.Interface().(type) {
+	case *big.Int:
+		return value.String()
+	case *big.Float:
+		return value.Text('g', -1)
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return EmptyTag
+		}
+		v = v.Elem()
+	}
+	switch value := v.Interface().(type) {
+	case time.Time:
+		if layout != EmptyTag {
+			return value.Format(layout)
+		}
+		return value.Format(time.RFC3339Nano)
+	case tagRange:
+		return value.formatRange()
+	case fmt.Stringer:
+		return value.String()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes())
+		}
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// tagRange is implemented by every Range so that ranges can be formatted without knowing N.
+type tagRange interface {
+	formatRange() string
+}
+
+// marshalFlags returns the names of the flags in a flag set that make up a value joined by '|'.
+func marshalFlags(flagSet map[string]int64, value int64) (string, error) {
+	names := make([]string, 0)
+	var flags int64
+	for name, flag := range flagSet {
+		if flag != 0 && value&flag == flag {
+			names = append(names, name)
+			flags |= flag
+		}
+	}
+	if flags != value {
+		return EmptyTag, fmt.Errorf("value %d can not be made from the flags in the flag set", value)
+	}
+	sort.Strings(names)
+	return strings.Join(names, "|"), nil
+}
+
+// quoteTagValue quotes a value if it would otherwise not be read back the same from a struct tag
+// that uses sep between options (or from inside of a list or map). Backslashes in values that are
+// not quoted are escaped since they are only kept as is inside of quotes.
+func quoteTagValue(value string, sep string) string {
+	if !strings.ContainsAny(value, sep+"' =]}") && !strings.HasPrefix(value, "[") && !strings.HasPrefix(value, "{") {
+		return strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
@@ -0,0 +1,415 @@
+package spectagular
+
+import (
+	"reflect"
+	"time"
+)
+
+// Option is used to configure a StructTagCache when it is created.
+type Option func(*options)
+
+// options are the settings used by a StructTagCache to parse struct tags.
+type options struct {
+	transforms          map[string]func(reflect.Value) (reflect.Value, error)
+	tokenizer           Tokenizer
+	descriptionTag      string
+	allowDuplicateNames bool
+	baseDir             string
+	flagSets            map[string]map[string]int64
+	decodeEscapes       bool
+	trimSpace           bool
+	strictNumbers       bool
+	nameMetaSeparator   string
+	timeLocation        *time.Location
+	requireQuotedSpaces bool
+	stringerEnums       map[reflect.Type][]any
+	exhaustiveEnums     map[reflect.Type][]any
+	enums               map[reflect.Type]*enumResolver
+	strictBareValues    bool
+	discriminator       string
+	discriminatedTypes  map[string]reflect.Type
+	ignorePrefix        string
+	unknownValueMarker  func(field reflect.StructField, raw string)
+	resolverTimeout     time.Duration
+	secretProvider      func(ref string) (string, error)
+	allowedTypes        func(reflect.Type) bool
+	profile             string
+	recoverPanics       bool
+	separator           string
+	templates           map[string]string
+	caseInsensitiveKeys bool
+	decimalComma        bool
+	urlDecode           bool
+	errorRecovery       func(field reflect.StructField, key, raw string, err error) (reflect.Value, bool)
+	conversionHook      func(field reflect.StructField, v reflect.Value) (reflect.Value, error)
+	nameTransform       func(string) string
+	recursive           bool
+	unexported          bool
+	extraTagNames       []string
+	strictKeys          bool
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		transforms:      make(map[string]func(reflect.Value) (reflect.Value, error)),
+		tokenizer:       DefaultTokenizer{},
+		flagSets:        make(map[string]map[string]int64),
+		stringerEnums:   make(map[reflect.Type][]any),
+		exhaustiveEnums: make(map[reflect.Type][]any),
+		enums:           make(map[reflect.Type]*enumResolver),
+		timeLocation:    time.UTC,
+		separator:       ",",
+		templates:       make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// layer returns a copy of the options with more options applied on top of them. Maps are copied
+// so that the options being layered over are not changed.
+func (o options) layer(opts []Option) options {
+	layered := o
+	layered.transforms = make(map[string]func(reflect.Value) (reflect.Value, error), len(o.transforms))
+	for k, v := range o.transforms {
+		layered.transforms[k] = v
+	}
+	layered.flagSets = make(map[string]map[string]int64, len(o.flagSets))
+	for k, v := range o.flagSets {
+		layered.flagSets[k] = v
+	}
+	layered.stringerEnums = make(map[reflect.Type][]any, len(o.stringerEnums))
+	for k, v := range o.stringerEnums {
+		layered.stringerEnums[k] = v
+	}
+	layered.exhaustiveEnums = make(map[reflect.Type][]any, len(o.exhaustiveEnums))
+	for k, v := range o.exhaustiveEnums {
+		layered.exhaustiveEnums[k] = v
+	}
+	// enums are built from the registered enum values when the definition is read
+	layered.enums = make(map[reflect.Type]*enumResolver)
+	layered.templates = make(map[string]string, len(o.templates))
+	for k, v := range o.templates {
+		layered.templates[k] = v
+	}
+	for _, opt := range opts {
+		opt(&layered)
+	}
+	return layered
+}
+
+// WithTransform registers a function that can be applied to the value of any struct tag option
+// that references it by name (i.e. `structtag:"name,transform=upper"`). The function is
+// applied after the value has been resolved and any error it returns is treated the same as
+// a resolver error.
+func WithTransform(name string, fn func(reflect.Value) (reflect.Value, error)) Option {
+	return func(o *options) {
+		o.transforms[name] = fn
+	}
+}
+
+// WithTokenizer replaces the DefaultTokenizer used to split struct tags into options. This
+// allows for tags that do not follow the comma delimited grammar (i.e. `name:type:flag`) to be
+// parsed by the same resolvers.
+func WithTokenizer(tokenizer Tokenizer) Option {
+	return func(o *options) {
+		o.tokenizer = tokenizer
+	}
+}
+
+// WithDescriptionTag sets the name of a struct tag (i.e. `desc`) whose value is stored as the
+// Description of each parsed FieldTag.
+func WithDescriptionTag(tagName string) Option {
+	return func(o *options) {
+		o.descriptionTag = tagName
+	}
+}
+
+// WithAllowDuplicateNames allows multiple fields of the definition struct to use the same option
+// name instead of returning an error. Every field that shares a name is set when the option is
+// parsed and Lookup will return all of their options.
+func WithAllowDuplicateNames() Option {
+	return func(o *options) {
+		o.allowDuplicateNames = true
+	}
+}
+
+// WithBaseDir sets the directory that relative values of options marked as `path` are joined
+// with (and cleaned). Absolute paths are left untouched.
+func WithBaseDir(dir string) Option {
+	return func(o *options) {
+		o.baseDir = dir
+	}
+}
+
+// WithFlagSet registers a set of flag names and their values that can be referenced by any
+// integer option declared with `flagset=name`. Values of the form `flag|flag|...` are parsed by
+// OR-ing the value of each flag together and unknown flags return an error.
+func WithFlagSet(name string, flags map[string]int64) Option {
+	return func(o *options) {
+		o.flagSets[name] = flags
+	}
+}
+
+// WithDecodeEscapes decodes backslash escapes (\n, \t, \r, and \\) in string values so that
+// generated tags can contain characters like newlines. It is off by default. Escaped backslashes in
+// unquoted values are left for this to decode (i.e. `s=a\\n` is read as a backslash followed by
+// n), so they are only decoded once, while escaped separators and quotes are still read as is.
+func WithDecodeEscapes() Option {
+	return func(o *options) {
+		o.decodeEscapes = true
+	}
+}
+
+// WithTrimSpace removes any leading or trailing whitespace from values before they are parsed.
+func WithTrimSpace() Option {
+	return func(o *options) {
+		o.trimSpace = true
+	}
+}
+
+// WithStrictNumbers prevents numeric values (integers, floats, complex numbers, and durations)
+// from being trimmed by WithTrimSpace so that numbers with surrounding whitespace are always
+// rejected.
+func WithStrictNumbers() Option {
+	return func(o *options) {
+		o.strictNumbers = true
+	}
+}
+
+// WithNameMetaSeparator splits the first value of a tag (the name segment) on a separator (i.e. "|")
+// so that anything after it (i.e. the `string` in `test:"email|string,required"`) is stored as the
+// NameMeta of each FieldTag instead of being parsed as part of the name.
+func WithNameMetaSeparator(sep string) Option {
+	return func(o *options) {
+		o.nameMetaSeparator = sep
+	}
+}
+
+// WithTimeLocation sets the location used for time.Time values that are given without a time zone
+// (i.e. `2006-01-02 15:04:05`). By default these are parsed in UTC so that results do not depend
+// on the local time zone of the host.
+func WithTimeLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.timeLocation = loc
+	}
+}
+
+// WithRequireQuotedSpaces causes slice elements that contain a space to be rejected unless they
+// are quoted (i.e. `list=[a b,c]` is an error while `list=['a b',c]` is not). This catches values
+// that were meant to be separate elements but were missing a comma.
+func WithRequireQuotedSpaces() Option {
+	return func(o *options) {
+		o.requireQuotedSpaces = true
+	}
+}
+
+// WithStringerEnum registers the values of an enum type that implements fmt.Stringer so that
+// options of that type are parsed by matching the value against the String() of each of them
+// (i.e. `level=Info` is parsed as the value whose String() is "Info"). Every value must be of
+// the given type.
+func WithStringerEnum(rType reflect.Type, values []any) Option {
+	return func(o *options) {
+		o.stringerEnums[rType] = values
+	}
+}
+
+// WithStrictBareValues causes a value given without a key (i.e. `test:"value"`) to return an error
+// if it does not match the name of an option and there is no positional option left to claim it.
+// By default these values are ignored.
+func WithStrictBareValues() Option {
+	return func(o *options) {
+		o.strictBareValues = true
+	}
+}
+
+// WithDiscriminator allows interface options (or slices of them) to be parsed into one of several
+// concrete struct types. Values are of the form `key=value;key=value` where the value of the
+// discriminator key picks the type from types and every other key sets the field of that type
+// with the same (case insensitive) name (i.e. `items=[type=a;x=1,type=b;y=2]`).
+func WithDiscriminator(field string, types map[string]reflect.Type) Option {
+	return func(o *options) {
+		o.discriminator = field
+		o.discriminatedTypes = types
+	}
+}
+
+// WithIgnorePrefix causes any tag that starts with a prefix (i.e. `test:"//name=x"` for "//") to be
+// ignored entirely, so the parsed value of that field is left as the zero value and required
+// options are not checked. This allows tags to be adopted gradually.
+func WithIgnorePrefix(prefix string) Option {
+	return func(o *options) {
+		o.ignorePrefix = prefix
+	}
+}
+
+// WithUnknownValueMarker registers a function that is called with every value that fails to parse
+// for an option that is not required. These values are otherwise ignored, so this allows them to
+// be recorded or logged while parsing continues.
+func WithUnknownValueMarker(fn func(field reflect.StructField, raw string)) Option {
+	return func(o *options) {
+		o.unknownValueMarker = fn
+	}
+}
+
+// WithResolverTimeout bounds how long each call to a resolver can take before ErrResolverTimeout is
+// returned, which protects against custom resolvers that never return. Each call is run in its own
+// goroutine, so resolvers must be safe to call concurrently. Go has no way to stop a goroutine, so a
+// resolver that times out keeps running in the background (and its result is discarded) until it
+// returns on its own. Built-in resolvers for plain strings, bools, and numbers are not bounded.
+func WithResolverTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.resolverTimeout = d
+	}
+}
+
+// WithSecretProvider registers a function that resolves values with the "secret:" prefix (i.e.
+// `password=secret:db_password`) before they are parsed so that secrets are not kept in the tag
+// itself. Any error returned by the provider fails parsing even if the option is not required.
+func WithSecretProvider(provider func(ref string) (string, error)) Option {
+	return func(o *options) {
+		o.secretProvider = provider
+	}
+}
+
+// WithAllowedTypes registers a function that is called with every type before its struct tags are
+// parsed (i.e. by Add, GetOrAdd, or ValidateType), and any type it returns false for is rejected
+// with an error. This allows the types that can be parsed to be restricted by a policy.
+func WithAllowedTypes(allowed func(reflect.Type) bool) Option {
+	return func(o *options) {
+		o.allowedTypes = allowed
+	}
+}
+
+// WithProfile selects the options of a single profile from tags that group their options by
+// profile (i.e. `test:"prod:{host=p,port=80};dev:{host=d}"`). Only the options of the selected
+// profile are parsed, and tags that are not grouped by profile are parsed as is.
+func WithProfile(name string) Option {
+	return func(o *options) {
+		o.profile = name
+	}
+}
+
+// WithRecoverPanics recovers any panic from a resolver (or transform) and returns it as an error
+// for the type being parsed instead, so that one bad resolver cannot crash the program.
+func WithRecoverPanics() Option {
+	return func(o *options) {
+		o.recoverPanics = true
+	}
+}
+
+// WithSeparator changes the delimiter between options (and the values of lists) from a comma to
+// the rune given (i.e. ';' for tags like `column=id;primary`). The DefaultTokenizer is replaced by
+// one that uses the separator, so this should be given before WithTokenizer if both are used.
+func WithSeparator(r rune) Option {
+	return func(o *options) {
+		o.separator = string(r)
+		o.tokenizer = DefaultTokenizer{Separator: r}
+	}
+}
+
+// WithEnumExhaustive registers every declared value of an enum type so that options of that type
+// can only be one of them. Values are matched by their String() if the type implements
+// fmt.Stringer (or by fmt.Sprint otherwise) and unknown values return an error listing the allowed
+// values. The full set is available from the EnumResolver of the option (i.e. for documentation).
+func WithEnumExhaustive(rType reflect.Type, values []any) Option {
+	return func(o *options) {
+		o.exhaustiveEnums[rType] = values
+	}
+}
+
+// WithErrorRecovery registers a function that is called with the field, option name, raw value,
+// and error whenever a resolver fails to parse the value of an option. If it returns true the
+// value it returns is used instead, otherwise the error is handled as usual (i.e. returned for
+// required options and ignored for the rest). Timeouts and recovered panics are never recovered.
+func WithErrorRecovery(fn func(field reflect.StructField, key, raw string, err error) (reflect.Value, bool)) Option {
+	return func(o *options) {
+		o.errorRecovery = fn
+	}
+}
+
+// WithTemplate registers a tag string that can be referenced by name with an '@' prefix (i.e.
+// `test:"@defaults,port=80"`), in which case the reference is replaced by the options of the
+// template before the tag is parsed. A reference given to a key (i.e. `config=@defaults`) is
+// replaced by the template as is. Templates can reference other templates but not themselves.
+func WithTemplate(name, tagString string) Option {
+	return func(o *options) {
+		o.templates[name] = tagString
+	}
+}
+
+// WithCaseInsensitiveKeys matches the keys of options regardless of case (i.e. `omitEmpty`,
+// `omitempty`, and `OmitEmpty` are all the same option). Option names are lowercased, so Lookup
+// and GetRaw use the lowercase names.
+func WithCaseInsensitiveKeys() Option {
+	return func(o *options) {
+		o.caseInsensitiveKeys = true
+	}
+}
+
+// WithDecimalComma parses a comma in a float as the decimal point (i.e. `f='3,14'` is parsed as
+// 3.14) for locales that write numbers that way. Since unquoted commas separate options, these
+// values need to be quoted. Floats with a decimal point (i.e. `f=3.14`) are still parsed as usual.
+func WithDecimalComma() Option {
+	return func(o *options) {
+		o.decimalComma = true
+	}
+}
+
+// WithURLDecode decodes percent-encoded values (i.e. `q=a%2Cb`) before they are parsed. Decoding
+// happens before lists are parsed, so encoded delimiters and brackets of slice and map options are
+// treated the same as if they were not encoded (i.e. `q=%5Ba%2Cb%5D` is parsed as the list `[a,b]`),
+// and before secrets are resolved. Values that are not valid percent-encoding are treated as values that failed to
+// parse.
+func WithURLDecode() Option {
+	return func(o *options) {
+		o.urlDecode = true
+	}
+}
+
+// WithConversionHook calls hook with every resolved option value before it is converted and set
+// on the parsed struct so that values can be coerced generically (i.e. lowercasing the host of a
+// parsed URL). The value returned by hook is set in place of the resolved value, and errors are
+// handled the same as resolver errors (only returned for required options).
+func WithConversionHook(hook func(field reflect.StructField, v reflect.Value) (reflect.Value, error)) Option {
+	return func(o *options) {
+		o.conversionHook = hook
+	}
+}
+
+// WithNameTransform transforms the field name that an empty $name option defaults to (i.e. a
+// snake_case transform would make an empty name on UserID default to user_id).
+func WithNameTransform(transform func(string) string) Option {
+	return func(o *options) {
+		o.nameTransform = transform
+	}
+}
+
+// WithRecursive also parses and caches the struct tags of every struct type used by a field (or
+// the element type of slices, arrays, and pointers of structs) when a type is added, so that
+// nested definitions can be looked up by their own types. Structs without exported fields (i.e.
+// time.Time) are skipped and a type is only added if every type nested in it parses.
+func WithRecursive() Option {
+	return func(o *options) {
+		o.recursive = true
+	}
+}
+
+// WithUnexported also parses the struct tags of unexported fields, which are skipped otherwise.
+// Only the struct tag is parsed, so the FieldTag of an unexported field can be used to reflect on
+// the field but the field still can not be set from outside of its package.
+func WithUnexported() Option {
+	return func(o *options) {
+		o.unexported = true
+	}
+}
+
+// WithStrictKeys returns an error for options given with a key that does not match any option
+// (i.e. a typo like `requried=true`) instead of ignoring them. Values given without a key are
+// still handled by WithStrictBareValues.
+func WithStrictKeys() Option {
+	return func(o *options) {
+		o.strictKeys = true
+	}
+}
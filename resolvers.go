@@ -1,10 +1,23 @@
 package spectagular
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
+var (
+	registryLock sync.RWMutex
+	registry     = make(map[reflect.Type]StructTagOptionUnmarshaler)
+)
+
 // StructTagOptionUnmarshaler is an interface used to convert a string value extracted
 // from a field's struct tag options and convert it to its expected value. It should also
 // return any errors involved with processing if any.
@@ -12,13 +25,36 @@ type StructTagOptionUnmarshaler interface {
 	UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error)
 }
 
+// MultiFieldUnmarshaler is an interface used to convert a single value extracted from a field's
+// struct tag options into the values of multiple fields of the definition struct (i.e. size=10x20
+// could set both a Width and Height field). The returned map is keyed by the index of each field
+// in the definition struct. It can be implemented by the type of a definition struct field or by
+// the Resolver of an option provided by a TagSchemaProvider.
+type MultiFieldUnmarshaler interface {
+	UnmarshalTagOptions(field reflect.StructField, value string) (map[int]reflect.Value, error)
+}
+
+// multiFieldResolver wraps a MultiFieldUnmarshaler so that it can be used as a resolver
+type multiFieldResolver struct {
+	MultiFieldUnmarshaler
+}
+
+func (m *multiFieldResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	return reflect.ValueOf(nil), errors.New("option sets multiple fields and cannot be resolved to a single value")
+}
+
 // nameResolver is used to parse tags that use the first value as a "name"
-// and default to the field name (i.e. json, yaml, etc.)
+// and default to the field name (i.e. json, yaml, etc.) or the field name transformed by transform
+// if it is set
 type nameResolver struct {
-	resolver StructTagOptionUnmarshaler
+	resolver  StructTagOptionUnmarshaler
+	transform func(string) string
 }
 
 func (n *nameResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if value == EmptyTag && n.transform != nil {
+		return n.resolver.UnmarshalTagOption(field, n.transform(field.Name))
+	}
 	if value == EmptyTag {
 		return n.resolver.UnmarshalTagOption(field, field.Name)
 	}
@@ -26,6 +62,7 @@ func (n *nameResolver) UnmarshalTagOption(field reflect.StructField, value strin
 }
 
 // boolResolver is used to parse tags of boolean values. if the key is present it is set to true
+// and if it is present with a leading '!' (i.e. `!omitempty`) it is set to false
 type boolResolver struct {
 	key string
 }
@@ -34,6 +71,9 @@ func (b *boolResolver) UnmarshalTagOption(field reflect.StructField, value strin
 	if value == b.key {
 		return reflect.ValueOf(true), nil
 	}
+	if value == "!"+b.key {
+		return reflect.ValueOf(false), nil
+	}
 	return convertToValue(value, reflect.Bool)
 }
 
@@ -53,32 +93,37 @@ func (p *pointerResolver) UnmarshalTagOption(field reflect.StructField, valueStr
 	return value, err
 }
 
-// arrayResolver is used to parse anything as an array
+// sliceResolver is used to parse anything as a slice. a single value without brackets (i.e. `id=5`)
+// is parsed the same as a list with one value (i.e. `id=[5]`)
 type sliceResolver struct {
-	resolver       StructTagOptionUnmarshaler
-	underlyingType reflect.Type
+	resolver            StructTagOptionUnmarshaler
+	underlyingType      reflect.Type
+	requireQuotedSpaces bool
+	separator           string
+	// decodeEscapes keeps escaped backslashes for the escapeResolver of the elements
+	decodeEscapes bool
 }
 
 func (s *sliceResolver) UnmarshalTagOption(field reflect.StructField, tag string) (reflect.Value, error) {
 	valueStr := ""
 	value := reflect.MakeSlice(reflect.SliceOf(s.underlyingType), 0, 0)
-	if len(tag) > 0 {
-		if tag[0] == ',' {
-			tag = "," + tag
-		}
-		if tag[len(tag)-1] == ',' {
-			tag += ","
-		}
+	if strings.HasPrefix(tag, s.separator) {
+		tag = s.separator + tag
+	}
+	if strings.HasSuffix(tag, s.separator) {
+		tag += s.separator
 	}
 	var err error
 	for tag != EmptyTag {
-		if tag[0] == ',' {
-			tag = tag[1:]
-		}
-		tag, valueStr, err = getNextTagValue(tag)
+		tag = strings.TrimPrefix(tag, s.separator)
+		quoted := tag != EmptyTag && tag[0] == '\''
+		tag, valueStr, err = getNextTagValue(tag, s.separator, s.decodeEscapes)
 		if err != nil {
 			return reflect.ValueOf(nil), err
 		}
+		if s.requireQuotedSpaces && !quoted && strings.Contains(valueStr, " ") {
+			return reflect.ValueOf(nil), fmt.Errorf("unquoted slice element '%s' contains a space", valueStr)
+		}
 		val, err := s.resolver.UnmarshalTagOption(field, valueStr)
 		if err != nil {
 			return reflect.ValueOf(nil), err
@@ -88,53 +133,733 @@ func (s *sliceResolver) UnmarshalTagOption(field reflect.StructField, tag string
 	return value, nil
 }
 
-// durationResolver is used to parse a duration string
-type durationResolver struct{}
+// locationResolver is used to parse time zone names (i.e. America/New_York) with time.LoadLocation
+type locationResolver struct {
+	pointer bool
+}
+
+func (l *locationResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return reflect.ValueOf(nil), fmt.Errorf("unknown time zone '%s': %w", value, err)
+	}
+	if l.pointer {
+		return reflect.ValueOf(loc), nil
+	}
+	return reflect.ValueOf(loc).Elem(), nil
+}
+
+// timeLayouts are the layouts tried (in order) when parsing a time.Time. RFC3339 is tried first
+// and the rest are parsed in the configured location since they do not include a time zone.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// timeResolver is used to parse timestamps of any of the timeLayouts (or only layout if it is set).
+// timestamps without a time zone are parsed in loc
+type timeResolver struct {
+	loc    *time.Location
+	layout string
+}
+
+func (t *timeResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if t.layout != EmptyTag {
+		ts, err := time.ParseInLocation(t.layout, value, t.loc)
+		if err != nil {
+			return reflect.ValueOf(nil), fmt.Errorf("invalid timestamp '%s' for layout '%s'", value, t.layout)
+		}
+		return reflect.ValueOf(ts), nil
+	}
+	for _, layout := range timeLayouts {
+		if ts, err := time.ParseInLocation(layout, value, t.loc); err == nil {
+			return reflect.ValueOf(ts), nil
+		}
+	}
+	return reflect.ValueOf(nil), fmt.Errorf("invalid timestamp '%s'", value)
+}
+
+// mapResolver is used to parse maps of the form `{key=value,key=value}` (or the older form
+// `key:value;key:value`) where each key and value is parsed by the resolver for the map's key and
+// value type. if ordered is set the map is returned as that OrderedMap type along with its keys
+type mapResolver struct {
+	keyResolver StructTagOptionUnmarshaler
+	resolver    StructTagOptionUnmarshaler
+	mapType     reflect.Type
+	ordered     reflect.Type
+	separator   string
+	// decodeEscapes keeps escaped backslashes for the escapeResolver of the values
+	decodeEscapes bool
+}
+
+func (m *mapResolver) UnmarshalTagOption(field reflect.StructField, tag string) (reflect.Value, error) {
+	value := reflect.MakeMap(m.mapType)
+	keys := reflect.MakeSlice(reflect.SliceOf(m.mapType.Key()), 0, 0)
+	trimmed := trimBrackets(tag)
+	if err := m.setPairs(field, value, &keys, trimmed, trimmed != tag); err != nil {
+		return reflect.ValueOf(nil), err
+	}
+	if m.ordered == nil {
+		return value, nil
+	}
+	ordered := reflect.New(m.ordered).Elem()
+	ordered.FieldByName("Keys").Set(keys)
+	ordered.FieldByName("Values").Set(value)
+	return ordered, nil
+}
+
+// setPairs resolves every pair of a map and sets them in value while appending each new key to
+// keys in the order they were given. Pairs are read as `k=v,...` if the map was bracketed or if
+// an '=' comes before any ':' and as `k:v;...` otherwise.
+func (m *mapResolver) setPairs(field reflect.StructField, value reflect.Value, keys *reflect.Value, tag string, bracketed bool) error {
+	if tag == EmptyTag {
+		return nil
+	}
+	if i := strings.IndexAny(tag, "=:"); !bracketed && (i < 0 || tag[i] == ':') {
+		for _, pair := range strings.Split(tag, ";") {
+			k, v, ok := strings.Cut(pair, ":")
+			if !ok {
+				return fmt.Errorf("missing ':' in map pair '%s'", pair)
+			}
+			if err := m.setPair(field, value, keys, pair, k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for tag != EmptyTag {
+		tag = strings.TrimPrefix(tag, m.separator)
+		k, rest, ok := strings.Cut(tag, "=")
+		if !ok {
+			return fmt.Errorf("missing '=' in map pair '%s'", tag)
+		}
+		var v string
+		var err error
+		if tag, v, err = getNextTagValue(rest, m.separator, m.decodeEscapes); err != nil {
+			return err
+		}
+		if err := m.setPair(field, value, keys, k+"="+v, k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setPair resolves a key and value and sets them in a map, appending the key to keys if it is new.
+func (m *mapResolver) setPair(field reflect.StructField, value reflect.Value, keys *reflect.Value, pair string, k string, v string) error {
+	key, err := m.keyResolver.UnmarshalTagOption(field, k)
+	if err != nil {
+		return fmt.Errorf("invalid key in map pair '%s': %w", pair, err)
+	}
+	if !key.CanConvert(m.mapType.Key()) {
+		return fmt.Errorf("unable to convert key in map pair '%s' to type '%s'", pair, m.mapType.Key())
+	}
+	val, err := m.resolver.UnmarshalTagOption(field, v)
+	if err != nil {
+		return fmt.Errorf("invalid value in map pair '%s': %w", pair, err)
+	}
+	if !val.CanConvert(m.mapType.Elem()) {
+		return fmt.Errorf("unable to convert value in map pair '%s' to type '%s'", pair, m.mapType.Elem())
+	}
+	key = key.Convert(m.mapType.Key())
+	if !value.MapIndex(key).IsValid() {
+		*keys = reflect.Append(*keys, key)
+	}
+	value.SetMapIndex(key, val.Convert(m.mapType.Elem()))
+	return nil
+}
+
+// OrderedMap[K comparable, V any] can be used as the type of a struct tag field to parse maps the
+// same way as a map[K]V while keeping the order that their keys were first given in (i.e.
+// `weights={b=2,a=1}` has the Keys b and a).
+type OrderedMap[K comparable, V any] struct {
+	Keys   []K
+	Values map[K]V
+}
+
+// mapType returns the type of the map that the pairs of the OrderedMap are parsed into.
+func (m OrderedMap[K, V]) mapType() reflect.Type {
+	return reflect.TypeOf(m.Values)
+}
+
+// orderedMap is implemented by every OrderedMap so that they can be parsed without knowing K and V.
+type orderedMap interface {
+	mapType() reflect.Type
+}
+
+// durationResolver is used to parse a duration string. if a unit is set it is used for values
+// that are just a number
+type durationResolver struct {
+	unit string
+}
 
 func (d *durationResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if d.unit != EmptyTag {
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			value += d.unit
+		}
+	}
 	dur, err := time.ParseDuration(value)
 	return reflect.ValueOf(dur), err
 }
 
-// defaultResolver is used to parse any other values
-type defaultResolver struct {
+// Number is a constraint for any type that can be used as the bounds of a Range.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Range[N Number] can be used as the type of a struct tag field to parse ranges of the form
+// `lo:hi` where either end can be omitted (i.e. `1:10`, `:10`, or `5:`). HasLo and HasHi denote
+// whether or not the corresponding end of the range was given.
+type Range[N Number] struct {
+	Lo    N
+	Hi    N
+	HasLo bool
+	HasHi bool
+}
+
+func (r Range[N]) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	lo, hi, ok := strings.Cut(value, ":")
+	if !ok {
+		return reflect.ValueOf(nil), errors.New("missing ':' in range: " + value)
+	}
+	rng := Range[N]{}
+	nType := reflect.TypeOf(rng.Lo)
+	if lo != EmptyTag {
+		v, err := convertToValue(lo, nType.Kind())
+		if err != nil {
+			return reflect.ValueOf(nil), err
+		}
+		rng.Lo = v.Convert(nType).Interface().(N)
+		rng.HasLo = true
+	}
+	if hi != EmptyTag {
+		v, err := convertToValue(hi, nType.Kind())
+		if err != nil {
+			return reflect.ValueOf(nil), err
+		}
+		rng.Hi = v.Convert(nType).Interface().(N)
+		rng.HasHi = true
+	}
+	return reflect.ValueOf(rng), nil
+}
+
+// formatRange formats the range the same way it is written in a struct tag.
+func (r Range[N]) formatRange() string {
+	rng := ":"
+	if r.HasLo {
+		rng = formatTagValue(reflect.ValueOf(r.Lo), EmptyTag) + rng
+	}
+	if r.HasHi {
+		rng += formatTagValue(reflect.ValueOf(r.Hi), EmptyTag)
+	}
+	return rng
+}
+
+// pathResolver is used to parse file paths that are relative to a base directory. absolute paths
+// are left untouched
+type pathResolver struct {
+	baseDir string
+}
+
+func (p *pathResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if filepath.IsAbs(value) {
+		return reflect.ValueOf(value), nil
+	}
+	return reflect.ValueOf(filepath.Join(p.baseDir, value)), nil
+}
+
+// flagResolver is used to parse values of the form `flag|flag|...` by OR-ing the registered value
+// of each flag together. the result must fit in the integer type of the option
+type flagResolver struct {
+	flags map[string]int64
+	fType reflect.Type
+}
+
+func (f *flagResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	var flags int64
+	for _, name := range strings.Split(value, "|") {
+		flag, ok := f.flags[name]
+		if !ok {
+			return reflect.ValueOf(nil), fmt.Errorf("unknown flag '%s'", name)
+		}
+		flags |= flag
+	}
+	v := reflect.New(f.fType).Elem()
+	if v.CanInt() && v.OverflowInt(flags) || v.CanUint() && (flags < 0 || v.OverflowUint(uint64(flags))) {
+		return reflect.ValueOf(nil), fmt.Errorf("flags '%s' overflow type '%s'", value, f.fType)
+	}
+	return reflect.ValueOf(flags), nil
+}
+
+// unionResolver is used to parse interfaces of the form `key=value;key=value` into the concrete
+// type chosen by the value of the discriminator key
+type unionResolver struct {
+	iface   reflect.Type
+	options *options
+}
+
+func (u *unionResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	pairs := make(map[string]string)
+	keys := make([]string, 0)
+	for _, pair := range strings.Split(value, ";") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return reflect.ValueOf(nil), fmt.Errorf("missing '=' in pair '%s'", pair)
+		}
+		pairs[k] = v
+		keys = append(keys, k)
+	}
+	rType, ok := u.options.discriminatedTypes[pairs[u.options.discriminator]]
+	if !ok {
+		return reflect.ValueOf(nil), fmt.Errorf("unknown %s '%s'", u.options.discriminator, pairs[u.options.discriminator])
+	}
+	structType := rType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	ptr := reflect.New(structType)
+	for _, k := range keys {
+		if k == u.options.discriminator {
+			continue
+		}
+		f, ok := structType.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, k)
+		})
+		if !ok || f.PkgPath != EmptyTag {
+			return reflect.ValueOf(nil), fmt.Errorf("unknown field '%s' for type: %s", k, structType)
+		}
+		v, err := getResolver(f.Type, StructTagOption{Name: k}, u.options).UnmarshalTagOption(f, pairs[k])
+		if err != nil {
+			return reflect.ValueOf(nil), fmt.Errorf("invalid value for field '%s': %w", k, err)
+		}
+		fv := ptr.Elem().FieldByIndex(f.Index)
+		if !v.CanConvert(fv.Type()) {
+			return reflect.ValueOf(nil), fmt.Errorf("unable to convert value for field '%s' to type '%s'", k, fv.Type())
+		}
+		fv.Set(v.Convert(fv.Type()))
+	}
+	concrete := ptr.Elem()
+	if rType.Kind() == reflect.Pointer {
+		concrete = ptr
+	}
+	if !concrete.Type().Implements(u.iface) {
+		return reflect.ValueOf(nil), fmt.Errorf("type %s does not implement %s", rType, u.iface)
+	}
+	result := reflect.New(u.iface).Elem()
+	result.Set(concrete)
+	return result, nil
+}
+
+// rawMessageResolver is used to capture a value as is for json.RawMessage fields. The value must
+// be valid JSON.
+type rawMessageResolver struct{}
+
+func (r *rawMessageResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if !json.Valid([]byte(value)) {
+		return reflect.ValueOf(nil), fmt.Errorf("invalid JSON for field '%s': %s", field.Name, value)
+	}
+	return reflect.ValueOf(json.RawMessage(value)), nil
+}
+
+// EnumResolver is implemented by the resolvers of enum types registered with WithStringerEnum or
+// WithEnumExhaustive (i.e. the Resolver of an option found with Lookup) so that the allowed values
+// of an option can be listed (i.e. for documentation).
+type EnumResolver interface {
+	StructTagOptionUnmarshaler
+	Options() []string
+}
+
+// bigResolver is used to parse arbitrary precision numbers into a *big.Int or *big.Float (if float
+// is set). integers can be given in any base that big.Int.SetString accepts with a prefix (i.e.
+// 0x1f) and floats are parsed with enough precision to hold every digit given.
+type bigResolver struct {
+	float bool
+}
+
+func (b *bigResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if b.float {
+		prec := uint(len(value)) * 4
+		if prec < 64 {
+			prec = 64
+		}
+		f, _, err := big.ParseFloat(value, 10, prec, big.ToNearestEven)
+		if err != nil {
+			return reflect.ValueOf(nil), fmt.Errorf("invalid big float '%s'", value)
+		}
+		return reflect.ValueOf(f), nil
+	}
+	i, ok := new(big.Int).SetString(value, 0)
+	if !ok {
+		return reflect.ValueOf(nil), fmt.Errorf("invalid big integer '%s'", value)
+	}
+	return reflect.ValueOf(i), nil
+}
+
+// enumResolver is used to parse values of an enum type by the String() of each value
+type enumResolver struct {
+	values map[string]reflect.Value
+	names  []string
+}
+
+func (e *enumResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	v, ok := e.values[value]
+	if !ok {
+		return reflect.ValueOf(nil), fmt.Errorf("unknown enum value '%s' (expected one of: %s)", value, strings.Join(e.names, ", "))
+	}
+	return v, nil
+}
+
+// Options returns every value of the enum in the order they were registered.
+func (e *enumResolver) Options() []string {
+	return append([]string(nil), e.names...)
+}
+
+// getEnum maps the String() of each enum value to the value itself. Values of types that do not
+// implement fmt.Stringer are only allowed if stringer is false, in which case they are formatted
+// with fmt.Sprint.
+func getEnum(rType reflect.Type, values []any, stringer bool) (*enumResolver, error) {
+	enum := &enumResolver{
+		values: make(map[string]reflect.Value),
+		names:  make([]string, 0, len(values)),
+	}
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		if !v.IsValid() || v.Type() != rType {
+			return nil, fmt.Errorf("enum value '%v' is not of type: %s", value, rType)
+		}
+		_, isStringer := value.(fmt.Stringer)
+		if stringer && !isStringer {
+			return nil, fmt.Errorf("enum type does not implement fmt.Stringer: %s", rType)
+		}
+		name := fmt.Sprint(value)
+		if _, ok := enum.values[name]; ok {
+			return nil, fmt.Errorf("enum value '%s' is registered more than once for type: %s", name, rType)
+		}
+		enum.values[name] = v
+		enum.names = append(enum.names, name)
+	}
+	return enum, nil
+}
+
+// escapeResolver is used to parse strings that contain backslash escapes (\n, \t, \r, and \\)
+type escapeResolver struct{}
+
+var escapeReplacer = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t", `\r`, "\r")
+
+func (e *escapeResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	return reflect.ValueOf(escapeReplacer.Replace(value)), nil
+}
+
+// percentResolver is used to parse floats with a trailing '%' (i.e. 50% is parsed as 0.5)
+type percentResolver struct {
+	kind         reflect.Kind
+	decimalComma bool
+}
+
+func (p *percentResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if p.decimalComma {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	if !strings.HasSuffix(value, "%") {
+		return convertToValue(value, p.kind)
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return reflect.ValueOf(nil), err
+	}
+	if p.kind == reflect.Float32 {
+		return reflect.ValueOf(float32(v / 100)), nil
+	}
+	return reflect.ValueOf(v / 100), nil
+}
+
+// byteSizeResolver is used to parse human readable byte sizes (i.e. 10MB or 10MiB) into integers.
+// sizes are computed exactly, so fractions (i.e. 1.5KB) are only allowed if they are a whole
+// number of bytes and negative sizes are not allowed
+type byteSizeResolver struct {
 	kind reflect.Kind
 }
 
+var byteSizeUnits = map[string]uint64{
+	"":    1,
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+}
+
+func (b *byteSizeResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	i := strings.LastIndexAny(value, "0123456789.") + 1
+	unit, ok := byteSizeUnits[value[i:]]
+	if !ok {
+		return reflect.ValueOf(nil), fmt.Errorf("unknown byte size unit '%s'", value[i:])
+	}
+	if strings.HasPrefix(value, "-") {
+		return reflect.ValueOf(nil), fmt.Errorf("byte size '%s' can not be negative", value)
+	}
+	whole, fraction, _ := strings.Cut(value[:i], ".")
+	digits := whole + fraction
+	if digits == EmptyTag || strings.Trim(digits, "0123456789") != EmptyTag {
+		return reflect.ValueOf(nil), fmt.Errorf("invalid byte size '%s'", value)
+	}
+	size, _ := new(big.Int).SetString(digits, 10)
+	size.Mul(size, new(big.Int).SetUint64(unit))
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(fraction))), nil)
+	size, remainder := size.QuoRem(size, scale, new(big.Int))
+	if remainder.Sign() != 0 {
+		return reflect.ValueOf(nil), fmt.Errorf("byte size '%s' is not a whole number of bytes", value)
+	}
+	return convertToValue(size.String(), b.kind)
+}
+
+// defaultResolver is used to parse any other values. if decimalComma is set a comma in a float is
+// parsed as the decimal point (i.e. 3,14)
+type defaultResolver struct {
+	kind         reflect.Kind
+	decimalComma bool
+}
+
 func (d *defaultResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if d.decimalComma {
+		value = strings.Replace(value, ",", ".", 1)
+	}
 	return convertToValue(value, d.kind)
 }
 
-func getResolver(fType reflect.Type, name string) StructTagOptionUnmarshaler {
-	if name == NameTag {
+// isListResolver returns whether or not a resolver (or the resolver of a pointer) splits its value
+// into a slice or map, in which case it is given values with their escapes kept.
+func isListResolver(r StructTagOptionUnmarshaler) bool {
+	if pointer, ok := r.(*pointerResolver); ok {
+		r = pointer.resolver
+	}
+	switch r.(type) {
+	case *sliceResolver, *mapResolver:
+		return true
+	}
+	return false
+}
+
+// isBracketedResolver returns whether or not a resolver (or the resolver of a pointer) is given
+// bracketed values with their brackets (i.e. raw JSON, which keeps its value as is, and maps,
+// which use them to tell which form the pairs are in).
+func isBracketedResolver(r StructTagOptionUnmarshaler) bool {
+	if pointer, ok := r.(*pointerResolver); ok {
+		r = pointer.resolver
+	}
+	switch r.(type) {
+	case *rawMessageResolver, *mapResolver:
+		return true
+	}
+	return false
+}
+
+// isScalarResolver returns whether or not a resolver only parses a string, bool, integer, or float
+// that can be set directly by setScalar.
+func isScalarResolver(r StructTagOptionUnmarshaler) bool {
+	switch r := r.(type) {
+	case *boolResolver:
+		return true
+	case *defaultResolver:
+		if r.decimalComma {
+			return false
+		}
+		switch r.kind {
+		case reflect.String, reflect.Float32, reflect.Float64:
+			return true
+		}
+		return isIntegerKind(r.kind)
+	}
+	return false
+}
+
+// setScalar parses a value directly into a string, bool, integer, or float field. It behaves the
+// same as a boolResolver or defaultResolver but avoids allocating intermediate values.
+func setScalar(fv reflect.Value, key string, value string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		if value == key || value == "!"+key {
+			fv.SetBool(value == key)
+			return nil
+		}
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(v)
+	default:
+		return errors.New("unable to convert string to kind: " + fv.Kind().String())
+	}
+	return nil
+}
+
+// RegisterResolver registers a resolver for a type that is used by every StructTagCache created
+// afterwards. This allows for parsing types that cannot implement StructTagOptionUnmarshaler
+// (i.e. types from other packages). Resolvers are looked up when a cache is created, so
+// registration should ideally happen before any caches are created (i.e. in an init function),
+// but it is safe to register resolvers while other goroutines are creating caches or parsing.
+func RegisterResolver(rType reflect.Type, r StructTagOptionUnmarshaler) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[rType] = r
+}
+
+// getRegisteredResolver returns the resolver registered for a type if there is one.
+func getRegisteredResolver(fType reflect.Type) (StructTagOptionUnmarshaler, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	r, ok := registry[fType]
+	return r, ok
+}
+
+func getResolver(fType reflect.Type, opt StructTagOption, o *options) StructTagOptionUnmarshaler {
+	if opt.Name == NameTag {
+		inner := opt
+		inner.Name = EmptyTag
 		return &nameResolver{
-			resolver: getResolver(fType, ""),
+			resolver:  getResolver(fType, inner, o),
+			transform: o.nameTransform,
+		}
+	}
+	if fType.Implements(reflect.TypeOf((*MultiFieldUnmarshaler)(nil)).Elem()) {
+		return &multiFieldResolver{
+			MultiFieldUnmarshaler: reflect.New(fType).Interface().(MultiFieldUnmarshaler),
 		}
 	}
 	if fType.Implements(reflect.TypeOf((*StructTagOptionUnmarshaler)(nil)).Elem()) {
 		return reflect.New(fType).Interface().(StructTagOptionUnmarshaler)
 	}
+	if r, ok := getRegisteredResolver(fType); ok {
+		return r
+	}
+	if enum, ok := o.enums[fType]; ok {
+		return enum
+	}
 	if fType == reflect.TypeOf(*new(time.Duration)) {
-		return &durationResolver{}
+		return &durationResolver{
+			unit: opt.Unit,
+		}
+	}
+	if fType == reflect.TypeOf((*time.Location)(nil)) || fType == reflect.TypeOf(time.Location{}) {
+		return &locationResolver{
+			pointer: fType.Kind() == reflect.Pointer,
+		}
+	}
+	if fType == reflect.TypeOf(time.Time{}) {
+		return &timeResolver{
+			loc:    o.timeLocation,
+			layout: opt.Layout,
+		}
+	}
+	if fType == reflect.TypeOf(json.RawMessage{}) {
+		return &rawMessageResolver{}
+	}
+	if fType == reflect.TypeOf((*big.Int)(nil)) || fType == reflect.TypeOf((*big.Float)(nil)) {
+		return &bigResolver{
+			float: fType == reflect.TypeOf((*big.Float)(nil)),
+		}
+	}
+	if fType.Kind() == reflect.Interface && o.discriminator != EmptyTag {
+		return &unionResolver{
+			iface:   fType,
+			options: o,
+		}
 	}
 	if fType.Kind() == reflect.Slice {
 		return &sliceResolver{
-			resolver:       getResolver(fType.Elem(), name),
-			underlyingType: fType.Elem(),
+			resolver:            getResolver(fType.Elem(), opt, o),
+			underlyingType:      fType.Elem(),
+			requireQuotedSpaces: o.requireQuotedSpaces,
+			separator:           o.separator,
+			decodeEscapes:       o.decodeEscapes,
+		}
+	}
+	if ordered, ok := reflect.Zero(fType).Interface().(orderedMap); ok && fType.Kind() == reflect.Struct {
+		return &mapResolver{
+			keyResolver:   getResolver(ordered.mapType().Key(), StructTagOption{}, o),
+			resolver:      getResolver(ordered.mapType().Elem(), opt, o),
+			mapType:       ordered.mapType(),
+			ordered:       fType,
+			separator:     o.separator,
+			decodeEscapes: o.decodeEscapes,
+		}
+	}
+	if fType.Kind() == reflect.Map {
+		return &mapResolver{
+			keyResolver:   getResolver(fType.Key(), StructTagOption{}, o),
+			resolver:      getResolver(fType.Elem(), opt, o),
+			mapType:       fType,
+			separator:     o.separator,
+			decodeEscapes: o.decodeEscapes,
 		}
 	}
 	if fType.Kind() == reflect.Pointer {
 		return &pointerResolver{
-			resolver:       getResolver(fType.Elem(), name),
+			resolver:       getResolver(fType.Elem(), opt, o),
 			underlyingType: fType,
 		}
 	}
+	if opt.FlagSet != EmptyTag {
+		return &flagResolver{
+			flags: o.flagSets[opt.FlagSet],
+			fType: fType,
+		}
+	}
+	if opt.ByteSize {
+		return &byteSizeResolver{
+			kind: fType.Kind(),
+		}
+	}
+	if opt.Percent {
+		return &percentResolver{
+			kind:         fType.Kind(),
+			decimalComma: o.decimalComma,
+		}
+	}
+	if opt.Path && fType.Kind() == reflect.String {
+		return &pathResolver{
+			baseDir: o.baseDir,
+		}
+	}
+	if o.decodeEscapes && fType.Kind() == reflect.String {
+		return &escapeResolver{}
+	}
 	if fType.Kind() == reflect.Bool {
 		return &boolResolver{
-			key: name,
+			key: opt.Name,
 		}
 	}
 	return &defaultResolver{
-		kind: fType.Kind(),
+		kind:         fType.Kind(),
+		decimalComma: o.decimalComma && (fType.Kind() == reflect.Float32 || fType.Kind() == reflect.Float64),
 	}
 }
@@ -0,0 +1,1032 @@
+package spectagular_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matt1484/spectagular"
+)
+
+func TestDurationUnits(t *testing.T) {
+	type TestDurationTag struct {
+		Timeout time.Duration `structtag:"timeout,unit=ms"`
+		Delay   time.Duration `structtag:"delay"`
+	}
+	type TestDurationStruct struct {
+		Bare     int `test:"timeout=500"`
+		Explicit int `test:"timeout=2s"`
+		NoUnit   int `test:"delay=5m"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestDurationTag]("test")
+	if err != nil {
+		t.Fatal("TestDurationUnits: failed unit validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestDurationStruct{}))
+	if err != nil {
+		t.Fatal("TestDurationUnits: failed duration tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Timeout, 500*time.Millisecond, "TestDurationUnits: wrong parsed duration value:")
+	assertEqual(t, tags[1].Value.Timeout, 2*time.Second, "TestDurationUnits: wrong parsed duration value:")
+	assertEqual(t, tags[2].Value.Delay, 5*time.Minute, "TestDurationUnits: wrong parsed duration value:")
+	type TestDurationInvalid struct {
+		Timeout time.Duration `structtag:"timeout,unit=lightyears"`
+	}
+	badCache, err := spectagular.NewFieldTagCache[TestDurationInvalid]("test")
+	if badCache != nil || err == nil {
+		t.Error("TestDurationUnits: failed unknown unit validation")
+	}
+}
+
+func TestRanges(t *testing.T) {
+	type TestRangeTag struct {
+		Cols  spectagular.Range[int]     `structtag:"cols"`
+		Ratio spectagular.Range[float64] `structtag:"ratio"`
+	}
+	type TestRangeStruct struct {
+		Closed    int `test:"cols=1:10,ratio=0.5:1.5"`
+		LeftOpen  int `test:"cols=:10"`
+		RightOpen int `test:"cols=-5:"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestRangeTag]("test")
+	if err != nil {
+		t.Fatal("TestRanges: failed range validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestRangeStruct{}))
+	if err != nil {
+		t.Fatal("TestRanges: failed range tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Cols.Lo, 1, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[0].Value.Cols.Hi, 10, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[0].Value.Cols.HasLo, true, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[0].Value.Cols.HasHi, true, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[0].Value.Ratio.Lo, 0.5, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[0].Value.Ratio.Hi, 1.5, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[1].Value.Cols.HasLo, false, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[1].Value.Cols.Hi, 10, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[1].Value.Cols.HasHi, true, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[2].Value.Cols.Lo, -5, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[2].Value.Cols.HasLo, true, "TestRanges: wrong parsed range value:")
+	assertEqual(t, tags[2].Value.Cols.HasHi, false, "TestRanges: wrong parsed range value:")
+}
+
+type registeredType struct {
+	V string
+}
+
+type registeredResolver struct{}
+
+func (r registeredResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	return reflect.ValueOf(registeredType{V: value}), nil
+}
+
+func TestRegisterResolverConcurrent(t *testing.T) {
+	type TestRegisteredTag struct {
+		Registered registeredType `structtag:"r"`
+	}
+	type TestRegisteredStruct struct {
+		Field int `test:"r=registered"`
+	}
+	spectagular.RegisterResolver(reflect.TypeOf(registeredType{}), registeredResolver{})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			spectagular.RegisterResolver(reflect.TypeOf(registeredType{}), registeredResolver{})
+		}()
+		go func() {
+			defer wg.Done()
+			tags, err := spectagular.ParseTagsForType[TestRegisteredTag]("test", reflect.TypeOf(TestRegisteredStruct{}))
+			if err != nil {
+				t.Error("TestRegisterResolverConcurrent: failed registered tags validation", err.Error())
+				return
+			}
+			assertEqual(t, tags[0].Value.Registered.V, "registered", "TestRegisterResolverConcurrent: wrong parsed value:")
+		}()
+	}
+	wg.Wait()
+}
+
+type ipResolver struct{}
+
+func (r ipResolver) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return reflect.ValueOf(nil), fmt.Errorf("invalid ip '%s'", value)
+	}
+	return reflect.ValueOf(ip), nil
+}
+
+func TestRegisterResolver(t *testing.T) {
+	type TestIPTag struct {
+		IP    net.IP   `structtag:"ip,required"`
+		Peers []net.IP `structtag:"peers"`
+	}
+	type TestIPStruct struct {
+		Field int `test:"ip=10.0.0.1,peers=[::1,192.168.0.1]"`
+	}
+	type TestIPInvalid struct {
+		Field int `test:"ip=localhost"`
+	}
+	spectagular.RegisterResolver(reflect.TypeOf(net.IP{}), ipResolver{})
+	cache, err := spectagular.NewFieldTagCache[TestIPTag]("test")
+	if err != nil {
+		t.Fatal("TestRegisterResolver: failed registered type validation", err.Error())
+	}
+	if err := cache.Add(reflect.TypeOf(TestIPStruct{})); err != nil {
+		t.Fatal("TestRegisterResolver: failed registered tags validation", err.Error())
+	}
+	tags, _ := cache.Get(reflect.TypeOf(TestIPStruct{}))
+	assertEqual(t, tags[0].Value.IP.String(), "10.0.0.1", "TestRegisterResolver: wrong parsed value:")
+	assertEqual(t, len(tags[0].Value.Peers), 2, "TestRegisterResolver: wrong parsed slice length:")
+	assertEqual(t, tags[0].Value.Peers[0].String(), "::1", "TestRegisterResolver: wrong parsed slice value:")
+	err = cache.Add(reflect.TypeOf(TestIPInvalid{}))
+	if err == nil || !strings.Contains(err.Error(), "localhost") {
+		t.Error("TestRegisterResolver: failed invalid registered value validation", err)
+	}
+}
+
+func TestBigNumbers(t *testing.T) {
+	type TestBigTag struct {
+		Amount *big.Int   `structtag:"amount,required"`
+		Ratio  *big.Float `structtag:"ratio"`
+		Hex    *big.Int   `structtag:"hex"`
+		List   []*big.Int `structtag:"list"`
+	}
+	type TestBigStruct struct {
+		Field int `test:"amount=123456789012345678901234567890,ratio=3.14159265358979323846264338327950288,hex=0xffffffffffffffffff,list=[1,-18446744073709551616]"`
+	}
+	type TestBigInvalid struct {
+		Field int `test:"amount=12abc"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestBigTag]("test")
+	if err != nil {
+		t.Fatal("TestBigNumbers: failed big number validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestBigStruct{}))
+	if err != nil {
+		t.Fatal("TestBigNumbers: failed big number tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Amount.String(), "123456789012345678901234567890", "TestBigNumbers: wrong big int:")
+	assertEqual(t, tags[0].Value.Ratio.Text('f', 35), "3.14159265358979323846264338327950288", "TestBigNumbers: wrong big float:")
+	assertEqual(t, tags[0].Value.Hex.String(), "4722366482869645213695", "TestBigNumbers: wrong hex big int:")
+	assertEqual(t, len(tags[0].Value.List), 2, "TestBigNumbers: wrong big int slice length:")
+	assertEqual(t, tags[0].Value.List[1].String(), "-18446744073709551616", "TestBigNumbers: wrong big int slice value:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestBigInvalid{}))
+	if err == nil || !strings.Contains(err.Error(), "invalid big integer '12abc'") {
+		t.Error("TestBigNumbers: failed invalid big int validation", err)
+	}
+}
+
+func TestMaps(t *testing.T) {
+	type TestMapTag struct {
+		Weights  map[string]int           `structtag:"weights"`
+		Timeouts map[string]time.Duration `structtag:"timeouts"`
+	}
+	type TestMapStruct struct {
+		Typed int `test:"weights='a:1;b:2',timeouts='read:5s;write:1m'"`
+		Empty int `test:"weights=''"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestMapTag]("test")
+	if err != nil {
+		t.Fatal("TestMaps: failed map validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestMapStruct{}))
+	if err != nil {
+		t.Fatal("TestMaps: failed map tags validation", err.Error())
+	}
+	assertEqual(t, len(tags[0].Value.Weights), 2, "TestMaps: wrong parsed map length:")
+	assertEqual(t, tags[0].Value.Weights["a"], 1, "TestMaps: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Weights["b"], 2, "TestMaps: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Timeouts["read"], 5*time.Second, "TestMaps: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Timeouts["write"], time.Minute, "TestMaps: wrong parsed map value:")
+	assertEqual(t, len(tags[1].Value.Weights), 0, "TestMaps: wrong parsed map length:")
+	type TestMapRequiredTag struct {
+		Weights map[string]int `structtag:"weights,required"`
+	}
+	type TestMapInvalid struct {
+		Bad int `test:"weights='a:1;b:two'"`
+	}
+	_, err = spectagular.ParseTagsForType[TestMapRequiredTag]("test", reflect.TypeOf(TestMapInvalid{}))
+	if err == nil || !strings.Contains(err.Error(), "b:two") {
+		t.Error("TestMaps: failed invalid map pair validation", err)
+	}
+	type TestMapStringTag struct {
+		Values map[string]string `structtag:"values,required"`
+	}
+	type TestMapFirstSeparator struct {
+		Legacy  int `test:"values='a:x=y;b:2'"`
+		Braced  int `test:"values={a:x=y}"`
+		Pairs   int `test:"values='a=x:y'"`
+		Default int `test:"values='{a:x=y}'"`
+	}
+	separatorTags, err := spectagular.ParseTagsForType[TestMapStringTag]("test", reflect.TypeOf(TestMapFirstSeparator{}))
+	if err != nil {
+		t.Fatal("TestMaps: failed map separator tags validation", err.Error())
+	}
+	assertEqual(t, len(separatorTags[0].Value.Values), 2, "TestMaps: wrong legacy map length:")
+	assertEqual(t, separatorTags[0].Value.Values["a"], "x=y", "TestMaps: wrong legacy map value:")
+	assertEqual(t, separatorTags[0].Value.Values["b"], "2", "TestMaps: wrong legacy map value:")
+	assertEqual(t, separatorTags[1].Value.Values["a:x"], "y", "TestMaps: wrong braced map value:")
+	assertEqual(t, separatorTags[2].Value.Values["a"], "x:y", "TestMaps: wrong map value:")
+	assertEqual(t, separatorTags[3].Value.Values["a:x"], "y", "TestMaps: wrong quoted braced map value:")
+	type TestMapInvalidPair struct {
+		Bad int `test:"values='a:x=y;b'"`
+	}
+	_, err = spectagular.ParseTagsForType[TestMapStringTag]("test", reflect.TypeOf(TestMapInvalidPair{}))
+	if err == nil || !strings.Contains(err.Error(), "missing ':'") {
+		t.Error("TestMaps: failed invalid legacy map pair validation", err)
+	}
+}
+
+func TestBraceMaps(t *testing.T) {
+	type TestBraceMapTag struct {
+		Weights map[string]int    `structtag:"weights"`
+		Headers map[string]string `structtag:"headers"`
+		Ports   map[int]bool      `structtag:"ports"`
+	}
+	type TestBraceMapStruct struct {
+		Braces   int `test:"weights={a=1,b=2},headers={x='k=v',y='1,2'},ports={80=true,443=false}"`
+		Brackets int `test:"weights=[a=3,b=4]"`
+		Empty    int `test:"weights={},headers=[]"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestBraceMapTag]("test")
+	if err != nil {
+		t.Fatal("TestBraceMaps: failed map validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestBraceMapStruct{}))
+	if err != nil {
+		t.Fatal("TestBraceMaps: failed map tags validation", err.Error())
+	}
+	assertEqual(t, len(tags[0].Value.Weights), 2, "TestBraceMaps: wrong parsed map length:")
+	assertEqual(t, tags[0].Value.Weights["a"], 1, "TestBraceMaps: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Weights["b"], 2, "TestBraceMaps: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Headers["x"], "k=v", "TestBraceMaps: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Headers["y"], "1,2", "TestBraceMaps: wrong parsed map value:")
+	assertEqual(t, len(tags[0].Value.Ports), 2, "TestBraceMaps: wrong parsed map length:")
+	assertEqual(t, tags[0].Value.Ports[80], true, "TestBraceMaps: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Ports[443], false, "TestBraceMaps: wrong parsed map value:")
+	assertEqual(t, tags[1].Value.Weights["a"], 3, "TestBraceMaps: wrong parsed map value:")
+	assertEqual(t, tags[1].Value.Weights["b"], 4, "TestBraceMaps: wrong parsed map value:")
+	assertEqual(t, tags[2].Value.Weights != nil, true, "TestBraceMaps: missing empty map:")
+	assertEqual(t, len(tags[2].Value.Weights), 0, "TestBraceMaps: wrong parsed map length:")
+	assertEqual(t, len(tags[2].Value.Headers), 0, "TestBraceMaps: wrong parsed map length:")
+	type TestMapRequiredTag struct {
+		Ports map[int]bool `structtag:"ports,required"`
+	}
+	type TestMapInvalidKey struct {
+		Bad int `test:"ports={http=true}"`
+	}
+	_, err = spectagular.ParseTagsForType[TestMapRequiredTag]("test", reflect.TypeOf(TestMapInvalidKey{}))
+	if err == nil || !strings.Contains(err.Error(), "http=true") {
+		t.Error("TestBraceMaps: failed invalid map key validation", err)
+	}
+}
+
+func TestOrderedMaps(t *testing.T) {
+	type TestOrderedMapTag struct {
+		Weights  spectagular.OrderedMap[string, int]           `structtag:"weights"`
+		Timeouts spectagular.OrderedMap[string, time.Duration] `structtag:"timeouts"`
+		Codes    *spectagular.OrderedMap[int, string]          `structtag:"codes"`
+	}
+	type TestOrderedMapStruct struct {
+		Braces int `test:"weights={c=3,a=1,b=2,a=4},codes={404=missing,200=ok}"`
+		Legacy int `test:"timeouts='write:1m;read:5s'"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestOrderedMapTag]("test")
+	if err != nil {
+		t.Fatal("TestOrderedMaps: failed ordered map validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestOrderedMapStruct{}))
+	if err != nil {
+		t.Fatal("TestOrderedMaps: failed ordered map tags validation", err.Error())
+	}
+	assertEqual(t, strings.Join(tags[0].Value.Weights.Keys, ","), "c,a,b", "TestOrderedMaps: wrong key order:")
+	assertEqual(t, tags[0].Value.Weights.Values["a"], 4, "TestOrderedMaps: wrong repeated key value:")
+	assertEqual(t, tags[0].Value.Weights.Values["c"], 3, "TestOrderedMaps: wrong parsed map value:")
+	assertEqual(t, fmt.Sprint(tags[0].Value.Codes.Keys), "[404 200]", "TestOrderedMaps: wrong typed key order:")
+	assertEqual(t, tags[0].Value.Codes.Values[200], "ok", "TestOrderedMaps: wrong parsed map value:")
+	assertEqual(t, strings.Join(tags[1].Value.Timeouts.Keys, ","), "write,read", "TestOrderedMaps: wrong legacy key order:")
+	assertEqual(t, tags[1].Value.Timeouts.Values["read"], 5*time.Second, "TestOrderedMaps: wrong parsed map value:")
+	tag, err := cache.MarshalTagValue(tags[0].Value)
+	if err != nil {
+		t.Fatal("TestOrderedMaps: failed to marshal ordered map", err.Error())
+	}
+	assertEqual(t, tag, "weights={c=3,a=4,b=2},codes={404=missing,200=ok}", "TestOrderedMaps: wrong marshaled order:")
+}
+
+func TestTypedMapKeys(t *testing.T) {
+	type TestTypedKeyTag struct {
+		Codes  map[int]string `structtag:"codes,required"`
+		Counts map[string]int `structtag:"counts"`
+	}
+	type TestTypedKeyStruct struct {
+		Legacy int `test:"codes='200:ok;404:notfound',counts='a:1;b:2'"`
+		Braces int `test:"codes={500=error},counts={c=3}"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestTypedKeyTag]("test", reflect.TypeOf(TestTypedKeyStruct{}))
+	if err != nil {
+		t.Fatal("TestTypedMapKeys: failed map tags validation", err.Error())
+	}
+	assertEqual(t, len(tags[0].Value.Codes), 2, "TestTypedMapKeys: wrong parsed map length:")
+	assertEqual(t, tags[0].Value.Codes[200], "ok", "TestTypedMapKeys: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Codes[404], "notfound", "TestTypedMapKeys: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Counts["a"], 1, "TestTypedMapKeys: wrong parsed map value:")
+	assertEqual(t, tags[0].Value.Counts["b"], 2, "TestTypedMapKeys: wrong parsed map value:")
+	assertEqual(t, tags[1].Value.Codes[500], "error", "TestTypedMapKeys: wrong parsed map value:")
+	assertEqual(t, tags[1].Value.Counts["c"], 3, "TestTypedMapKeys: wrong parsed map value:")
+	type TestMalformedKeyStruct struct {
+		Bad int `test:"codes='200:ok;four:notfound'"`
+	}
+	_, err = spectagular.ParseTagsForType[TestTypedKeyTag]("test", reflect.TypeOf(TestMalformedKeyStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "invalid key in map pair 'four:notfound'") {
+		t.Error("TestTypedMapKeys: failed malformed key validation", err)
+	}
+	type TestMissingKeyStruct struct {
+		Bad int `test:"codes='200:ok;404'"`
+	}
+	_, err = spectagular.ParseTagsForType[TestTypedKeyTag]("test", reflect.TypeOf(TestMissingKeyStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "'404'") {
+		t.Error("TestTypedMapKeys: failed malformed pair validation", err)
+	}
+}
+
+func TestScalarSlices(t *testing.T) {
+	type TestScalarSliceTag struct {
+		IDs   []int     `structtag:"id"`
+		Names *[]string `structtag:"names"`
+	}
+	type TestScalarSliceStruct struct {
+		Scalar  int `test:"id=5,names=a"`
+		Bracket int `test:"id=[5,6],names=[a,b]"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestScalarSliceTag]("test")
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestScalarSliceStruct{}))
+	if err != nil {
+		t.Fatal("TestScalarSlices: failed scalar slice tags validation", err.Error())
+	}
+	assertEqual(t, len(tags[0].Value.IDs), 1, "TestScalarSlices: wrong scalar slice length:")
+	assertEqual(t, tags[0].Value.IDs[0], 5, "TestScalarSlices: wrong scalar slice value:")
+	assertEqual(t, len(*tags[0].Value.Names), 1, "TestScalarSlices: wrong scalar slice length:")
+	assertEqual(t, (*tags[0].Value.Names)[0], "a", "TestScalarSlices: wrong scalar slice value:")
+	assertEqual(t, len(tags[1].Value.IDs), 2, "TestScalarSlices: wrong slice length:")
+	assertEqual(t, tags[1].Value.IDs[1], 6, "TestScalarSlices: wrong slice value:")
+	assertEqual(t, strings.Join(*tags[1].Value.Names, "|"), "a|b", "TestScalarSlices: wrong slice value:")
+}
+
+func TestDecimalComma(t *testing.T) {
+	type TestDecimalTag struct {
+		F       float64   `structtag:"f"`
+		F32     float32   `structtag:"f32"`
+		Floats  []float64 `structtag:"floats"`
+		Percent float64   `structtag:"percent,percent"`
+		Count   int       `structtag:"count"`
+	}
+	type TestDecimalStruct struct {
+		Comma int `test:"f='3,14',f32='0,5',floats=['1,5','2,25',3],percent='12,5%',count=2"`
+		Point int `test:"f=3.14,f32=0.5,floats=[1.5,2.25]"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestDecimalTag]("test", spectagular.WithDecimalComma())
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestDecimalStruct{}))
+	if err != nil {
+		t.Fatal("TestDecimalComma: failed decimal comma tags validation", err.Error())
+	}
+	for _, tag := range tags {
+		assertEqual(t, tag.Value.F, 3.14, "TestDecimalComma: wrong float:")
+		assertEqual(t, tag.Value.F32, float32(0.5), "TestDecimalComma: wrong float32:")
+		assertEqual(t, len(tag.Value.Floats) >= 2, true, "TestDecimalComma: wrong float slice length:")
+		assertEqual(t, tag.Value.Floats[0], 1.5, "TestDecimalComma: wrong float slice value:")
+		assertEqual(t, tag.Value.Floats[1], 2.25, "TestDecimalComma: wrong float slice value:")
+	}
+	assertEqual(t, len(tags[0].Value.Floats), 3, "TestDecimalComma: wrong float slice length:")
+	assertEqual(t, tags[0].Value.Percent, 0.125, "TestDecimalComma: wrong percent:")
+	assertEqual(t, tags[0].Value.Count, 2, "TestDecimalComma: wrong count:")
+}
+
+func TestPointerSlices(t *testing.T) {
+	type TestPointerSliceTag struct {
+		Strings   *[]string        `structtag:"sa"`
+		Ints      *[]int           `structtag:"ia"`
+		Durations *[]time.Duration `structtag:"da"`
+	}
+	type TestPointerSliceStruct struct {
+		Set   int `test:"sa=[a,b],ia=[1,2],da=[1s,2m]"`
+		Unset int `test:""`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestPointerSliceTag]("test")
+	if err != nil {
+		t.Fatal("TestPointerSlices: failed pointer slice validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestPointerSliceStruct{}))
+	if err != nil {
+		t.Fatal("TestPointerSlices: failed pointer slice tags validation", err.Error())
+	}
+	if tags[0].Value.Strings == nil || tags[0].Value.Ints == nil || tags[0].Value.Durations == nil {
+		t.Fatal("TestPointerSlices: pointer slices were not set")
+	}
+	assertEqual(t, len(*tags[0].Value.Strings), 2, "TestPointerSlices: wrong parsed slice length:")
+	assertEqual(t, (*tags[0].Value.Strings)[0], "a", "TestPointerSlices: wrong parsed slice value:")
+	assertEqual(t, (*tags[0].Value.Strings)[1], "b", "TestPointerSlices: wrong parsed slice value:")
+	assertEqual(t, len(*tags[0].Value.Ints), 2, "TestPointerSlices: wrong parsed slice length:")
+	assertEqual(t, (*tags[0].Value.Ints)[0], 1, "TestPointerSlices: wrong parsed slice value:")
+	assertEqual(t, (*tags[0].Value.Ints)[1], 2, "TestPointerSlices: wrong parsed slice value:")
+	assertEqual(t, (*tags[0].Value.Durations)[1], 2*time.Minute, "TestPointerSlices: wrong parsed slice value:")
+	if tags[1].Value.Strings != nil {
+		t.Error("TestPointerSlices: unset pointer slice was set")
+	}
+	type TestPointerMatrix struct {
+		Matrix *[][]int `structtag:"m"`
+	}
+	badCache, err := spectagular.NewFieldTagCache[TestPointerMatrix]("test")
+	if badCache != nil || err == nil {
+		t.Error("TestPointerSlices: failed unsupported pointer type validation")
+	}
+}
+
+func TestPathTags(t *testing.T) {
+	type TestPathTag struct {
+		Cert  string   `structtag:"cert,path"`
+		Certs []string `structtag:"certs,path"`
+		Name  string   `structtag:"name"`
+	}
+	type TestPathStruct struct {
+		Relative int `test:"cert=certs/../certs/server.pem,certs=[a.pem,/abs/b.pem],name=certs/name"`
+		Absolute int `test:"cert=/etc/ssl//server.pem"`
+	}
+	base := filepath.Join("base", "dir")
+	cache, err := spectagular.NewFieldTagCacheWithOptions[TestPathTag]("test", spectagular.WithBaseDir(base))
+	if err != nil {
+		t.Fatal("TestPathTags: failed path validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestPathStruct{}))
+	if err != nil {
+		t.Fatal("TestPathTags: failed path tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Cert, filepath.Join(base, "certs", "server.pem"), "TestPathTags: wrong parsed path:")
+	assertEqual(t, tags[0].Value.Certs[0], filepath.Join(base, "a.pem"), "TestPathTags: wrong parsed path:")
+	assertEqual(t, tags[0].Value.Certs[1], "/abs/b.pem", "TestPathTags: wrong parsed path:")
+	assertEqual(t, tags[0].Value.Name, "certs/name", "TestPathTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Cert, "/etc/ssl//server.pem", "TestPathTags: absolute path was changed:")
+	type TestPathInvalid struct {
+		Port int `structtag:"port,path"`
+	}
+	badCache, err := spectagular.NewFieldTagCache[TestPathInvalid]("test")
+	if badCache != nil || err == nil {
+		t.Error("TestPathTags: failed non string path validation")
+	}
+}
+
+func TestFlagSets(t *testing.T) {
+	type TestFlagTag struct {
+		Flags uint8 `structtag:"flags,flagset=perms"`
+	}
+	type TestFlagStruct struct {
+		All  int `test:"flags=Read|Write|Exec"`
+		One  int `test:"flags=Write"`
+		None int `test:""`
+	}
+	perms := spectagular.WithFlagSet("perms", map[string]int64{"Read": 4, "Write": 2, "Exec": 1})
+	cache, err := spectagular.NewFieldTagCacheWithOptions[TestFlagTag]("test", perms)
+	if err != nil {
+		t.Fatal("TestFlagSets: failed flag set validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestFlagStruct{}))
+	if err != nil {
+		t.Fatal("TestFlagSets: failed flag set tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Flags, 7, "TestFlagSets: wrong parsed flags:")
+	assertEqual(t, tags[1].Value.Flags, 2, "TestFlagSets: wrong parsed flags:")
+	assertEqual(t, tags[2].Value.Flags, 0, "TestFlagSets: wrong parsed flags:")
+	type TestFlagRequiredTag struct {
+		Flags int `structtag:"flags,required,flagset=perms"`
+	}
+	type TestFlagInvalid struct {
+		Unknown int `test:"flags=Read|Delete"`
+	}
+	requiredCache, err := spectagular.NewFieldTagCacheWithOptions[TestFlagRequiredTag]("test", perms)
+	if err != nil {
+		t.Fatal("TestFlagSets: failed flag set validation", err.Error())
+	}
+	if _, err = requiredCache.GetOrAdd(reflect.TypeOf(TestFlagInvalid{})); err == nil {
+		t.Error("TestFlagSets: failed unknown flag validation")
+	}
+	type TestFlagNarrowTag struct {
+		Flags uint8 `structtag:"flags,required,flagset=wide"`
+	}
+	type TestFlagOverflow struct {
+		High int `test:"flags=High|Low"`
+	}
+	wide := spectagular.WithFlagSet("wide", map[string]int64{"High": 256, "Low": 1})
+	narrowCache, _ := spectagular.NewFieldTagCacheWithOptions[TestFlagNarrowTag]("test", wide)
+	if _, err = narrowCache.GetOrAdd(reflect.TypeOf(TestFlagOverflow{})); err == nil || !strings.Contains(err.Error(), "overflow") {
+		t.Error("TestFlagSets: failed flag overflow validation", err)
+	}
+	badCache, err := spectagular.NewFieldTagCache[TestFlagTag]("test")
+	if badCache != nil || err == nil {
+		t.Error("TestFlagSets: failed unknown flag set validation")
+	}
+}
+
+func TestDecodeEscapes(t *testing.T) {
+	type TestEscapeTag struct {
+		String string `structtag:"s"`
+	}
+	type TestEscapeStruct struct {
+		Unquoted int `test:"s=line one\\nline two"`
+		Quoted   int `test:"s='tab\\tand \\\\n'"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestEscapeTag]("test", spectagular.WithDecodeEscapes())
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestEscapeStruct{}))
+	if err != nil {
+		t.Fatal("TestDecodeEscapes: failed escape tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.String, "line one\nline two", "TestDecodeEscapes: wrong decoded value:")
+	assertEqual(t, tags[1].Value.String, "tab\tand \\n", "TestDecodeEscapes: wrong decoded value:")
+	defaultCache, _ := spectagular.NewFieldTagCache[TestEscapeTag]("test")
+	tags, _ = defaultCache.GetOrAdd(reflect.TypeOf(TestEscapeStruct{}))
+	assertEqual(t, tags[0].Value.String, "line one\\nline two", "TestDecodeEscapes: wrong default value:")
+}
+
+func TestLocations(t *testing.T) {
+	type TestLocationTag struct {
+		TZ       *time.Location `structtag:"tz,required"`
+		Fallback time.Location  `structtag:"fallback"`
+	}
+	type TestLocationStruct struct {
+		Zone int `test:"tz=America/New_York,fallback=UTC"`
+	}
+	type TestLocationInvalid struct {
+		Zone int `test:"tz=Not/A_Zone"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestLocationTag]("test")
+	if err != nil {
+		t.Fatal("TestLocations: failed location validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestLocationStruct{}))
+	if err != nil {
+		t.Fatal("TestLocations: failed location tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.TZ.String(), "America/New_York", "TestLocations: wrong parsed location:")
+	assertEqual(t, tags[0].Value.Fallback.String(), "UTC", "TestLocations: wrong parsed location:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestLocationInvalid{}))
+	if err == nil || !strings.Contains(err.Error(), "Not/A_Zone") {
+		t.Error("TestLocations: failed unknown location validation", err)
+	}
+}
+
+func TestPercents(t *testing.T) {
+	type TestPercentTag struct {
+		Rate  float64 `structtag:"rate,percent"`
+		Ratio float32 `structtag:"ratio,percent"`
+		Plain float64 `structtag:"plain"`
+	}
+	type TestPercentStruct struct {
+		Half int `test:"rate=50%,ratio=25%,plain=50%"`
+		Full int `test:"rate=100%,ratio=0.5"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestPercentTag]("test")
+	if err != nil {
+		t.Fatal("TestPercents: failed percent validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestPercentStruct{}))
+	if err != nil {
+		t.Fatal("TestPercents: failed percent tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Rate, 0.5, "TestPercents: wrong parsed percent:")
+	assertEqual(t, tags[0].Value.Ratio, 0.25, "TestPercents: wrong parsed percent:")
+	assertEqual(t, tags[0].Value.Plain, 0, "TestPercents: parsed percent without marker:")
+	assertEqual(t, tags[1].Value.Rate, 1.0, "TestPercents: wrong parsed percent:")
+	assertEqual(t, tags[1].Value.Ratio, 0.5, "TestPercents: wrong parsed value:")
+	type TestPercentRequiredTag struct {
+		Plain float64 `structtag:"plain,required"`
+	}
+	_, err = spectagular.ParseTagsForType[TestPercentRequiredTag]("test", reflect.TypeOf(TestPercentStruct{}))
+	if err == nil {
+		t.Error("TestPercents: failed percent without marker validation")
+	}
+}
+
+func TestByteSizes(t *testing.T) {
+	type TestByteSizeTag struct {
+		MaxSize int64  `structtag:"maxsize,bytesize"`
+		Small   uint16 `structtag:"small,bytesize"`
+	}
+	type TestByteSizeStruct struct {
+		Decimal int `test:"maxsize=10MB,small=1KiB"`
+		Binary  int `test:"maxsize=10MiB,small=1.5KB"`
+		Bare    int `test:"maxsize=512,small=2B"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestByteSizeTag]("test")
+	if err != nil {
+		t.Fatal("TestByteSizes: failed byte size validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestByteSizeStruct{}))
+	if err != nil {
+		t.Fatal("TestByteSizes: failed byte size tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.MaxSize, 10000000, "TestByteSizes: wrong parsed byte size:")
+	assertEqual(t, tags[0].Value.Small, 1024, "TestByteSizes: wrong parsed byte size:")
+	assertEqual(t, tags[1].Value.MaxSize, 10*1024*1024, "TestByteSizes: wrong parsed byte size:")
+	assertEqual(t, tags[1].Value.Small, 1500, "TestByteSizes: wrong parsed byte size:")
+	assertEqual(t, tags[2].Value.MaxSize, 512, "TestByteSizes: wrong parsed byte size:")
+	assertEqual(t, tags[2].Value.Small, 2, "TestByteSizes: wrong parsed byte size:")
+	type TestByteSizeRequiredTag struct {
+		MaxSize int64 `structtag:"maxsize,required,bytesize"`
+	}
+	type TestByteSizeInvalid struct {
+		Unknown int `test:"maxsize=10XB"`
+	}
+	_, err = spectagular.ParseTagsForType[TestByteSizeRequiredTag]("test", reflect.TypeOf(TestByteSizeInvalid{}))
+	if err == nil || !strings.Contains(err.Error(), "XB") {
+		t.Error("TestByteSizes: failed unknown unit validation", err)
+	}
+	type TestByteSizeExact struct {
+		Large int `test:"maxsize=9007199254740993"`
+		Whole int `test:"maxsize=1.25KiB"`
+	}
+	exact, err := spectagular.ParseTagsForType[TestByteSizeRequiredTag]("test", reflect.TypeOf(TestByteSizeExact{}))
+	if err != nil {
+		t.Fatal("TestByteSizes: failed exact byte size tags validation", err.Error())
+	}
+	assertEqual(t, exact[0].Value.MaxSize, 9007199254740993, "TestByteSizes: wrong exact byte size:")
+	assertEqual(t, exact[1].Value.MaxSize, 1280, "TestByteSizes: wrong fractional byte size:")
+	for _, invalid := range []string{"1.4B", "-1KB", "10000000TB", "1e3KB", "."} {
+		invalidType := reflect.StructOf([]reflect.StructField{{
+			Name: "Field",
+			Type: reflect.TypeOf(0),
+			Tag:  reflect.StructTag(`test:"maxsize=` + invalid + `"`),
+		}})
+		if _, err := spectagular.ParseTagsForType[TestByteSizeRequiredTag]("test", invalidType); err == nil {
+			t.Error("TestByteSizes: failed invalid byte size validation for", invalid)
+		}
+	}
+}
+
+func TestTimes(t *testing.T) {
+	type TestTimeTag struct {
+		At    time.Time  `structtag:"at"`
+		Until *time.Time `structtag:"until"`
+	}
+	type TestTimeStruct struct {
+		ZoneLess int `test:"at=2024-01-02 15:04:05,until=2024-01-02"`
+		Zoned    int `test:"at=2024-01-02T15:04:05-05:00"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestTimeTag]("test")
+	if err != nil {
+		t.Fatal("TestTimes: failed time validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestTimeStruct{}))
+	if err != nil {
+		t.Fatal("TestTimes: failed time tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.At.Location().String(), "UTC", "TestTimes: wrong default location:")
+	assertEqual(t, tags[0].Value.At.Format(time.RFC3339), "2024-01-02T15:04:05Z", "TestTimes: wrong parsed time:")
+	assertEqual(t, tags[0].Value.Until.Format(time.RFC3339), "2024-01-02T00:00:00Z", "TestTimes: wrong parsed time:")
+	assertEqual(t, tags[1].Value.At.UTC().Format(time.RFC3339), "2024-01-02T20:04:05Z", "TestTimes: wrong parsed zoned time:")
+	loc := time.FixedZone("TEST", 3600)
+	cache, _ = spectagular.NewFieldTagCacheWithOptions[TestTimeTag]("test", spectagular.WithTimeLocation(loc))
+	tags, err = cache.GetOrAdd(reflect.TypeOf(TestTimeStruct{}))
+	if err != nil {
+		t.Fatal("TestTimes: failed time location tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.At.Format(time.RFC3339), "2024-01-02T15:04:05+01:00", "TestTimes: wrong parsed time in location:")
+}
+
+func TestTimeLayouts(t *testing.T) {
+	type TestLayoutTag struct {
+		Created time.Time   `structtag:"created"`
+		Day     time.Time   `structtag:"day,layout=2006-01-02"`
+		Dates   []time.Time `structtag:"dates" layout:"Jan 2, 2006"`
+	}
+	type TestLayoutStruct struct {
+		Field int `test:"created=2023-01-02T15:04:05Z,day=2023-05-06,dates=['Feb 3, 2023','Mar 4, 2023']"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestLayoutTag]("test")
+	if err != nil {
+		t.Fatal("TestTimeLayouts: failed layout validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestLayoutStruct{}))
+	if err != nil {
+		t.Fatal("TestTimeLayouts: failed layout tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Created.Format(time.RFC3339), "2023-01-02T15:04:05Z", "TestTimeLayouts: wrong RFC3339 time:")
+	assertEqual(t, tags[0].Value.Day.Format(time.RFC3339), "2023-05-06T00:00:00Z", "TestTimeLayouts: wrong layout time:")
+	assertEqual(t, len(tags[0].Value.Dates), 2, "TestTimeLayouts: wrong layout slice length:")
+	assertEqual(t, tags[0].Value.Dates[1].Format("2006-01-02"), "2023-03-04", "TestTimeLayouts: wrong layout slice time:")
+	type TestLayoutRequiredTag struct {
+		Day time.Time `structtag:"day,required,layout=2006-01-02"`
+	}
+	type TestLayoutInvalid struct {
+		Field int `test:"day=2023-05-06T00:00:00Z"`
+	}
+	_, err = spectagular.ParseTagsForType[TestLayoutRequiredTag]("test", reflect.TypeOf(TestLayoutInvalid{}))
+	if err == nil || !strings.Contains(err.Error(), "2006-01-02") {
+		t.Error("TestTimeLayouts: failed invalid layout time validation", err)
+	}
+	type TestLayoutNonTime struct {
+		Day string `structtag:"day,layout=2006-01-02"`
+	}
+	if _, err = spectagular.NewFieldTagCache[TestLayoutNonTime]("test"); err == nil {
+		t.Error("TestTimeLayouts: failed non time layout validation")
+	}
+}
+
+func TestRequireQuotedSpaces(t *testing.T) {
+	type TestQuotedSpacesTag struct {
+		List []string `structtag:"list,required"`
+	}
+	type TestQuotedStruct struct {
+		Quoted int `test:"list=['a b',c]"`
+	}
+	type TestUnquotedStruct struct {
+		Unquoted int `test:"list=[a b,c]"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestQuotedSpacesTag]("test", spectagular.WithRequireQuotedSpaces())
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestQuotedStruct{}))
+	if err != nil {
+		t.Fatal("TestRequireQuotedSpaces: failed quoted tags validation", err.Error())
+	}
+	assertEqual(t, len(tags[0].Value.List), 2, "TestRequireQuotedSpaces: wrong list length:")
+	assertEqual(t, tags[0].Value.List[0], "a b", "TestRequireQuotedSpaces: wrong list value:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestUnquotedStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "a b") {
+		t.Error("TestRequireQuotedSpaces: failed unquoted space validation", err)
+	}
+	tags, err = spectagular.ParseTagsForType[TestQuotedSpacesTag]("test", reflect.TypeOf(TestUnquotedStruct{}))
+	if err != nil {
+		t.Fatal("TestRequireQuotedSpaces: failed default tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.List[0], "a b", "TestRequireQuotedSpaces: wrong default list value:")
+}
+
+type testLevel int
+
+const (
+	testLevelDebug testLevel = iota
+	testLevelInfo
+	testLevelError
+)
+
+func (l testLevel) String() string {
+	return [...]string{"Debug", "Info", "Error"}[l]
+}
+
+func TestStringerEnums(t *testing.T) {
+	type TestEnumTag struct {
+		Level  testLevel   `structtag:"level,required"`
+		Levels []testLevel `structtag:"levels"`
+	}
+	type TestEnumStruct struct {
+		Info  int `test:"level=Info"`
+		Error int `test:"level=Error,levels=[Debug,Info]"`
+	}
+	type TestEnumUnknown struct {
+		Unknown int `test:"level=Warn"`
+	}
+	levels := []any{testLevelDebug, testLevelInfo, testLevelError}
+	cache, err := spectagular.NewFieldTagCacheWithOptions[TestEnumTag]("test", spectagular.WithStringerEnum(reflect.TypeOf(testLevel(0)), levels))
+	if err != nil {
+		t.Fatal("TestStringerEnums: failed enum validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestEnumStruct{}))
+	if err != nil {
+		t.Fatal("TestStringerEnums: failed enum tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Level.String(), "Info", "TestStringerEnums: wrong parsed enum:")
+	assertEqual(t, tags[1].Value.Level.String(), "Error", "TestStringerEnums: wrong parsed enum:")
+	assertEqual(t, len(tags[1].Value.Levels), 2, "TestStringerEnums: wrong parsed enum slice:")
+	assertEqual(t, tags[1].Value.Levels[1].String(), "Info", "TestStringerEnums: wrong parsed enum slice:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestEnumUnknown{}))
+	if err == nil || !strings.Contains(err.Error(), "Warn") {
+		t.Error("TestStringerEnums: failed unknown enum validation", err)
+	}
+	_, err = spectagular.NewFieldTagCacheWithOptions[TestEnumTag]("test", spectagular.WithStringerEnum(reflect.TypeOf(testLevel(0)), []any{1}))
+	if err == nil {
+		t.Error("TestStringerEnums: failed enum value type validation")
+	}
+}
+
+type testColor string
+
+const (
+	testColorRed   testColor = "red"
+	testColorGreen testColor = "green"
+	testColorBlue  testColor = "blue"
+)
+
+func TestEnumExhaustive(t *testing.T) {
+	type TestColorTag struct {
+		Color  testColor   `structtag:"color,required"`
+		Colors []testColor `structtag:"colors"`
+	}
+	type TestColorStruct struct {
+		Field int `test:"color=green,colors=[red,blue]"`
+	}
+	type TestColorUnknown struct {
+		Field int `test:"color=purple"`
+	}
+	colors := []any{testColorRed, testColorGreen, testColorBlue}
+	cache, err := spectagular.NewFieldTagCacheWithOptions[TestColorTag]("test", spectagular.WithEnumExhaustive(reflect.TypeOf(testColorRed), colors))
+	if err != nil {
+		t.Fatal("TestEnumExhaustive: failed enum validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestColorStruct{}))
+	if err != nil {
+		t.Fatal("TestEnumExhaustive: failed enum tags validation", err.Error())
+	}
+	assertEqual(t, string(tags[0].Value.Color), "green", "TestEnumExhaustive: wrong parsed enum:")
+	assertEqual(t, len(tags[0].Value.Colors), 2, "TestEnumExhaustive: wrong parsed enum slice:")
+	assertEqual(t, string(tags[0].Value.Colors[1]), "blue", "TestEnumExhaustive: wrong parsed enum slice:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestColorUnknown{}))
+	if err == nil || !strings.Contains(err.Error(), "red, green, blue") {
+		t.Error("TestEnumExhaustive: failed unknown enum validation", err)
+	}
+	opts, _ := cache.Lookup("color")
+	enum, ok := opts[0].Resolver.(spectagular.EnumResolver)
+	if !ok {
+		t.Fatal("TestEnumExhaustive: resolver is not an EnumResolver")
+	}
+	assertEqual(t, strings.Join(enum.Options(), ","), "red,green,blue", "TestEnumExhaustive: wrong enum options:")
+	_, err = spectagular.NewFieldTagCacheWithOptions[TestColorTag]("test", spectagular.WithEnumExhaustive(reflect.TypeOf(testColorRed), []any{testColorRed, testColorRed}))
+	if err == nil {
+		t.Error("TestEnumExhaustive: failed duplicate enum value validation")
+	}
+}
+
+type testShape interface {
+	Area() float64
+}
+
+type testSquare struct {
+	Side float64
+}
+
+func (s testSquare) Area() float64 {
+	return s.Side * s.Side
+}
+
+type testRect struct {
+	Width  float64
+	Height float64
+}
+
+func (r *testRect) Area() float64 {
+	return r.Width * r.Height
+}
+
+func TestDiscriminators(t *testing.T) {
+	type TestUnionTag struct {
+		Shapes []testShape `structtag:"shapes,required"`
+		Shape  testShape   `structtag:"shape"`
+	}
+	type TestUnionStruct struct {
+		Field int `test:"shapes=[kind=square;side=2,kind=rect;width=2;height=3],shape='kind=rect;width=1;height=5'"`
+	}
+	type TestUnionUnknown struct {
+		Field int `test:"shapes=[kind=circle;radius=2]"`
+	}
+	types := map[string]reflect.Type{
+		"square": reflect.TypeOf(testSquare{}),
+		"rect":   reflect.TypeOf(&testRect{}),
+	}
+	cache, err := spectagular.NewFieldTagCacheWithOptions[TestUnionTag]("test", spectagular.WithDiscriminator("kind", types))
+	if err != nil {
+		t.Fatal("TestDiscriminators: failed discriminator validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestUnionStruct{}))
+	if err != nil {
+		t.Fatal("TestDiscriminators: failed discriminator tags validation", err.Error())
+	}
+	assertEqual(t, len(tags[0].Value.Shapes), 2, "TestDiscriminators: wrong number of shapes:")
+	square, ok := tags[0].Value.Shapes[0].(testSquare)
+	assertEqual(t, ok, true, "TestDiscriminators: wrong concrete type:")
+	assertEqual(t, square.Side, 2, "TestDiscriminators: wrong parsed field:")
+	rect, ok := tags[0].Value.Shapes[1].(*testRect)
+	assertEqual(t, ok, true, "TestDiscriminators: wrong concrete type:")
+	assertEqual(t, rect.Area(), 6, "TestDiscriminators: wrong parsed fields:")
+	assertEqual(t, tags[0].Value.Shape.Area(), 5, "TestDiscriminators: wrong parsed single value:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestUnionUnknown{}))
+	if err == nil || !strings.Contains(err.Error(), "circle") {
+		t.Error("TestDiscriminators: failed unknown discriminator validation", err)
+	}
+	if _, err = spectagular.NewFieldTagCache[TestUnionTag]("test"); err == nil {
+		t.Error("TestDiscriminators: failed interface without discriminator validation")
+	}
+}
+
+func TestRawMessages(t *testing.T) {
+	type TestRawMessageTag struct {
+		Raw     json.RawMessage  `structtag:"raw"`
+		Pointer *json.RawMessage `structtag:"pointer"`
+	}
+	type TestRawMessageStruct struct {
+		Object int `test:"raw='{\"a\":[1,2],\"b\":\"c\"}',pointer=123"`
+		Array  int `test:"raw='[1, 2, 3]'"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestRawMessageTag]("test", reflect.TypeOf(TestRawMessageStruct{}))
+	if err != nil {
+		t.Fatal("TestRawMessages: failed raw message tags validation", err.Error())
+	}
+	assertEqual(t, string(tags[0].Value.Raw), `{"a":[1,2],"b":"c"}`, "TestRawMessages: wrong raw message:")
+	assertEqual(t, string(*tags[0].Value.Pointer), "123", "TestRawMessages: wrong raw message:")
+	assertEqual(t, string(tags[1].Value.Raw), "[1, 2, 3]", "TestRawMessages: wrong raw message:")
+	var decoded []int
+	if err := json.Unmarshal(tags[1].Value.Raw, &decoded); err != nil || len(decoded) != 3 {
+		t.Error("TestRawMessages: failed to decode raw message", err)
+	}
+	type TestRawMessageBracketed struct {
+		Array  int `test:"raw=[1,2],pointer={\"a\":1}"`
+		Object int `test:"raw={\"a\":[1,2]}"`
+	}
+	tags, err = spectagular.ParseTagsForType[TestRawMessageTag]("test", reflect.TypeOf(TestRawMessageBracketed{}))
+	if err != nil {
+		t.Fatal("TestRawMessages: failed bracketed raw message tags validation", err.Error())
+	}
+	assertEqual(t, string(tags[0].Value.Raw), "[1,2]", "TestRawMessages: wrong bracketed raw message:")
+	assertEqual(t, string(*tags[0].Value.Pointer), `{"a":1}`, "TestRawMessages: wrong braced raw message:")
+	assertEqual(t, string(tags[1].Value.Raw), `{"a":[1,2]}`, "TestRawMessages: wrong braced raw message:")
+	type TestRawMessageRequiredTag struct {
+		Raw json.RawMessage `structtag:"raw,required"`
+	}
+	type TestRawMessageInvalid struct {
+		Field int `test:"raw=hello"`
+	}
+	if _, err := spectagular.ParseTagsForType[TestRawMessageRequiredTag]("test", reflect.TypeOf(TestRawMessageInvalid{})); err == nil {
+		t.Error("TestRawMessages: failed invalid raw message validation")
+	}
+}
+
+type slowValue string
+
+func (s slowValue) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if value == "slow" {
+		time.Sleep(100 * time.Millisecond)
+	}
+	return reflect.ValueOf(slowValue(value)), nil
+}
+
+func TestResolverTimeout(t *testing.T) {
+	type TestTimeoutTag struct {
+		Value slowValue `structtag:"value"`
+	}
+	type TestFastStruct struct {
+		Fast int `test:"value=fast"`
+	}
+	type TestSlowStruct struct {
+		Slow int `test:"value=slow"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestTimeoutTag]("test", spectagular.WithResolverTimeout(10*time.Millisecond))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestFastStruct{}))
+	if err != nil {
+		t.Fatal("TestResolverTimeout: failed fast tags validation", err.Error())
+	}
+	assertEqual(t, string(tags[0].Value.Value), "fast", "TestResolverTimeout: wrong parsed value:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestSlowStruct{}))
+	if !errors.Is(err, spectagular.ErrResolverTimeout) {
+		t.Error("TestResolverTimeout: failed timeout validation", err)
+	}
+}
+
+type panicValue string
+
+func (p panicValue) UnmarshalTagOption(field reflect.StructField, value string) (reflect.Value, error) {
+	if value == "panic" {
+		panic("bad value")
+	}
+	return reflect.ValueOf(panicValue(value)), nil
+}
+
+func TestRecoverPanics(t *testing.T) {
+	type TestPanicTag struct {
+		Value panicValue `structtag:"value"`
+	}
+	type TestPanicStruct struct {
+		Bad int `test:"value=panic"`
+	}
+	type TestNoPanicStruct struct {
+		Good int `test:"value=ok"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestPanicTag]("test", spectagular.WithRecoverPanics())
+	_, err := cache.GetOrAdd(reflect.TypeOf(TestPanicStruct{}))
+	if !errors.Is(err, spectagular.ErrResolverPanic) || !strings.Contains(err.Error(), "bad value") || !strings.Contains(err.Error(), "Bad") {
+		t.Error("TestRecoverPanics: failed panic validation", err)
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestNoPanicStruct{}))
+	if err != nil {
+		t.Fatal("TestRecoverPanics: failed tags validation after panic", err.Error())
+	}
+	assertEqual(t, string(tags[0].Value.Value), "ok", "TestRecoverPanics: wrong parsed value:")
+	timeoutCache, _ := spectagular.NewFieldTagCacheWithOptions[TestPanicTag]("test", spectagular.WithRecoverPanics(), spectagular.WithResolverTimeout(time.Second))
+	if _, err = timeoutCache.GetOrAdd(reflect.TypeOf(TestPanicStruct{})); !errors.Is(err, spectagular.ErrResolverPanic) {
+		t.Error("TestRecoverPanics: failed panic validation with timeout", err)
+	}
+}
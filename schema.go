@@ -0,0 +1,76 @@
+package spectagular
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// JSONSchema returns a minimal JSON schema (i.e. for OpenAPI documents) of a type (adding it to the
+// cache if needed) of the form `{"type":"object","properties":{...},"required":[...]}`. Properties
+// are named by the $name option of each field (or the field name if it is empty) and
+// fields named "-" are left out. A property is required if its "required" option is true.
+func (t *StructTagCache[T]) JSONSchema(rType reflect.Type) (map[string]any, error) {
+	tags, err := t.GetOrAdd(rType)
+	if err != nil {
+		return nil, err
+	}
+	nameOpts, hasName := t.Lookup(NameTag)
+	requiredOpts, hasRequired := t.Lookup(RequiredTag)
+	properties := make(map[string]any, len(tags))
+	required := make([]string, 0)
+	for _, tag := range tags {
+		name := tag.FieldName
+		v := reflect.Indirect(reflect.ValueOf(tag.Value))
+		if hasName && v.IsValid() {
+			if n := fmt.Sprint(v.FieldByIndex(nameOpts[0].fieldIndex()).Interface()); n != EmptyTag {
+				name = n
+			}
+		}
+		if name == SkipTag {
+			continue
+		}
+		properties[name] = jsonSchemaType(tag.FieldType)
+		if hasRequired && v.IsValid() {
+			if r := v.FieldByIndex(requiredOpts[0].fieldIndex()); r.Kind() == reflect.Bool && r.Bool() {
+				required = append(required, name)
+			}
+		}
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}, nil
+}
+
+// jsonSchemaType returns the JSON schema of a go type.
+func jsonSchemaType(rType reflect.Type) map[string]any {
+	for rType.Kind() == reflect.Pointer {
+		rType = rType.Elem()
+	}
+	if rType == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch rType.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if rType.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{"type": "array", "items": jsonSchemaType(rType.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchemaType(rType.Elem())}
+	case reflect.Struct:
+		return map[string]any{"type": "object"}
+	}
+	if isIntegerKind(rType.Kind()) {
+		return map[string]any{"type": "integer"}
+	}
+	return map[string]any{}
+}
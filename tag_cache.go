@@ -3,10 +3,13 @@ package spectagular
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -21,15 +24,97 @@ const (
 	// NameTag is used to denote the first field or the name of the field if empty
 	// (i.e. how its used for encoding/json, encoding/yaml, etc.).
 	NameTag = "$name"
+	// TransformTag is used to denote the name of a registered transform to apply to this struct
+	// tag field after it is resolved (i.e. transform=upper).
+	TransformTag = "transform"
+	// UnitTag is used to denote the default unit of a time.Duration struct tag field when the
+	// value is just a number (i.e. unit=ms).
+	UnitTag = "unit"
+	// PositionalTag is used to denote that this struct tag field claims the value at the given
+	// position in the tag if it is given without a key (i.e. positional=0).
+	PositionalTag = "positional"
+	// PathTag is used to denote that this struct tag field is a file path that should be joined
+	// with the base directory given by WithBaseDir if it is relative.
+	PathTag = "path"
+	// PercentTag is used to denote that this float struct tag field can be given as a percentage
+	// (i.e. 50% is parsed as 0.5).
+	PercentTag = "percent"
+	// ByteSizeTag is used to denote that this integer struct tag field can be given as a human
+	// readable byte size (i.e. 10MB or 10MiB).
+	ByteSizeTag = "bytesize"
+	// FlagSetTag is used to denote the name of a flag set registered with WithFlagSet that is
+	// used to parse values of the form flag|flag|... into this struct tag field (i.e. flagset=perms).
+	FlagSetTag = "flagset"
+	// MinLenTag is used to denote the minimum length of a slice struct tag field (i.e. minlen=1).
+	MinLenTag = "minlen"
+	// MaxLenTag is used to denote the maximum length of a slice struct tag field (i.e. maxlen=5).
+	MaxLenTag = "maxlen"
+	// TailTag is used to denote a string field that receives the rest of the tag as is (including
+	// any commas) after every positional option has been consumed.
+	TailTag = "$tail"
+	// DefaultTrueTag is used to denote that this bool struct tag field is true unless it is
+	// disabled with key=false or !key.
+	DefaultTrueTag = "defaulttrue"
+	// DefaultTag is used to denote the value used for this struct tag field when it is not given
+	// (i.e. default=10). It can also be given as a separate struct tag on the field of the
+	// definition struct (i.e. `default:"a,b"`) for defaults that contain commas.
+	DefaultTag = "default"
+	// ConflictsWithTag is used to denote the names of struct tag fields (separated by |) that
+	// cannot be given along with this one (i.e. conflictswith=file).
+	ConflictsWithTag = "conflictswith"
+	// LayoutTag is used to denote the layout used to parse a time.Time struct tag field (i.e.
+	// layout=2006-01-02). It can also be given as a separate struct tag on the field of the
+	// definition struct (i.e. `layout:"Jan 2, 2006"`) for layouts that contain commas.
+	LayoutTag = "layout"
+	// AliasTag is a separate struct tag on the field of the definition struct that gives other
+	// names for an option (i.e. `structtag:"primaryKey" alias:"pk"`), separated by commas.
+	AliasTag = "alias"
+	// MinTag and MaxTag are separate struct tags on the field of the definition struct that give
+	// the inclusive bounds of an integer or float option (i.e. `structtag:"workers" min:"1" max:"64"`).
+	// Bounds of time.Duration options are compared in nanoseconds.
+	MinTag = "min"
+	MaxTag = "max"
+	// EnumTag is a separate struct tag on the field of the definition struct that gives the only
+	// values allowed for a string or integer option, separated by commas (i.e.
+	// `structtag:"mode" enum:"read,write,append"`).
+	EnumTag = "enum"
 )
 
-var (
-	keyValueRegex         = regexp.MustCompile(`^(?:(\w+)=)?(.+)`)
-	untilNextCommaRegex   = regexp.MustCompile(`^([^,]*),?`)
-	untilNextQuoteRegex   = regexp.MustCompile(`^([^']*)'`)
-	untilNextBracketRegex = regexp.MustCompile(`^([^\]]*)]`)
-)
+// isNumericType returns whether or not a type (or the element type of a pointer/slice) is an
+// integer, float, or complex number.
+func isNumericType(rType reflect.Type) bool {
+	for rType.Kind() == reflect.Pointer || rType.Kind() == reflect.Slice {
+		rType = rType.Elem()
+	}
+	switch rType.Kind() {
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return isIntegerKind(rType.Kind())
+}
+
+// isMapKeyKind returns whether or not a kind can be used as the key of a map option.
+func isMapKeyKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return isIntegerKind(kind)
+}
 
+// isIntegerKind returns whether or not a kind is a signed or unsigned integer.
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// convertToValue parses a value into a value of the given kind. Floats are parsed with the bit size
+// of the kind (so float32 values are rounded once rather than from a float64) and accept anything
+// strconv.ParseFloat does, including -0.0 and hex floats (i.e. 0x1p-2).
 func convertToValue(value string, kind reflect.Kind) (reflect.Value, error) {
 	switch kind {
 	case reflect.Bool:
@@ -98,10 +183,30 @@ type FieldTag[V any] struct {
 	FieldName string
 	// FieldIndex is the index of the field that these tags apply too. It is included
 	// since most of the time when you are parsing struct tags you need to know
-	// some limited information about the field.
+	// some limited information about the field. For fields of embedded structs this is the index
+	// of the field in the embedded struct.
 	FieldIndex int
+	// FieldIndexPath is the index sequence of the field (as used by reflect.Value.FieldByIndex)
+	// so that fields of embedded structs can be found from the outer struct. Paths through
+	// embedded pointers can be followed with reflect.Value.FieldByIndexErr if they may be nil.
+	FieldIndexPath []int
+	// FieldType is the type of the field that these tags apply too. It is included so that
+	// the type can be checked without reflecting over the struct again.
+	FieldType reflect.Type
 	// Value is the parsed value of the struct tags for a field in a struct.
 	Value V
+	// Description is the value of the field's description tag if one was configured with
+	// WithDescriptionTag.
+	Description string
+	// NameMeta is any metadata following the name segment of the tag if a separator was
+	// configured with WithNameMetaSeparator (i.e. `string` in `test:"email|string"`).
+	NameMeta string
+	// Raw is the struct tag of the field exactly as it was written, before any processing (i.e.
+	// profiles, templates, or ignore prefixes).
+	Raw string
+
+	// scanned is every option scanned from the tag in order, keyed by the option it was given to
+	scanned []TagToken
 }
 
 // StructTagOption is the definition of an option for a defined struct tag type. An example being how
@@ -111,122 +216,519 @@ type StructTagOption struct {
 	Required   bool
 	FieldIndex int
 	Resolver   StructTagOptionUnmarshaler
+	// FieldIndexPath is the index sequence (as used by reflect.Type.FieldByIndex) of the field
+	// for options defined by a struct embedded in the definition struct. If it is empty then
+	// FieldIndex is used.
+	FieldIndexPath []int
+	// Transform is the name of a function registered with WithTransform that is applied to the
+	// resolved value.
+	Transform string
+	// Unit is the unit used for time.Duration values that are just a number.
+	Unit string
+	// Positional denotes that this option claims the value at Position in the tag if it was
+	// given without a key.
+	Positional bool
+	Position   int
+	// Path denotes that this option is a file path that is resolved against the base directory
+	// given by WithBaseDir.
+	Path bool
+	// Percent denotes that this option can be given as a percentage.
+	Percent bool
+	// ByteSize denotes that this option can be given as a human readable byte size.
+	ByteSize bool
+	// FlagSet is the name of a flag set registered with WithFlagSet that is used to parse this
+	// option.
+	FlagSet string
+	// MinLen and MaxLen are the bounds on the length of a slice option. A bound of 0 is not checked.
+	MinLen int
+	MaxLen int
+	// DefaultTrue denotes that this bool option is true unless it is disabled with key=false or !key.
+	DefaultTrue bool
+	// Default is the value that is parsed for this option when it is not given.
+	Default string
+	// ConflictsWith is the names of the options that cannot be given along with this one. Conflicts
+	// are symmetric, so they only need to be declared by one of the options.
+	ConflictsWith []string
+	// Layout is the layout used to parse time.Time values in place of the default layouts.
+	Layout string
+	// Aliases are other names that can be used to give this option (i.e. an older spelling).
+	Aliases []string
+	// Min and Max are the inclusive bounds of an integer or float option. A nil bound is not
+	// checked. Bounds of time.Duration options are in nanoseconds.
+	Min *float64
+	Max *float64
+	// AllowedValues are the only values allowed for a string or integer option. Nothing is checked
+	// if it is empty.
+	AllowedValues []string
+
+	transform func(reflect.Value) (reflect.Value, error)
+	numeric   bool
+	scalar    bool
+}
+
+// DefaultProvider is an interface that can be implemented by a definition struct to provide
+// defaults that are not static (i.e. based on time.Now). DefaultFor is called with the name of
+// every option that is not given and does not have a default marker, and the value returned (if
+// any) is parsed as if it was given.
+type DefaultProvider interface {
+	DefaultFor(option string) (string, bool)
+}
+
+// fieldIndex returns the index sequence of the field of the option in the definition struct.
+func (st StructTagOption) fieldIndex() []int {
+	if len(st.FieldIndexPath) > 0 {
+		return st.FieldIndexPath
+	}
+	return []int{st.FieldIndex}
+}
+
+// TagSchemaProvider is an interface that can be implemented by a definition struct to provide
+// its options programmatically. The options provided replace any options reflected from the
+// same fields of the definition struct. Options without a Resolver will use the default resolver
+// for the type of their field.
+type TagSchemaProvider interface {
+	TagSchema() []StructTagOption
 }
 
 // StructTagCache[T any] is a cache for parsed struct tags. It is used to parse a struct's tag defined
 // by type T and store them as mapping of the struct's type to []FieldTag[T] for easy lookup later.
 // While tags could be parsed as needed, this struct is designed for workflows like encoding/json
-// where the same type may need its struct tags parsed more than once.
+// where the same type may need its struct tags parsed more than once. It is safe for concurrent use.
 type StructTagCache[T any] struct {
+	lock         sync.RWMutex
 	tagName      string
 	typeToTags   map[reflect.Type][]FieldTag[T]
-	structTagMap map[string]StructTagOption
+	nameToTypes  map[string][]reflect.Type
+	structTagMap map[string][]StructTagOption
+	names        []string
+	aliases      map[string]string
+	conflicts    map[string][]string
 	hasName      bool
+	hasTail      bool
+	tailStart    int
+	defaultTrue  [][]int
+	positional   map[int]string
 	requiredTags []string
+	options      options
 }
 
 // NewFieldTagCache[T any] initializes a StructTagCache for type T.
 func NewFieldTagCache[T any](tagName string) (*StructTagCache[T], error) {
+	return NewFieldTagCacheWithOptions[T](tagName)
+}
+
+// NewFieldTagCacheMulti[T any] initializes a StructTagCache for type T that merges the options
+// given in several struct tags (i.e. `json:"id" validate:"required"`) into one T per field. Tags
+// are read in the order of their names so later names override earlier ones when they give the
+// same option, and only the first tag name is used for positional options (i.e. $name), for
+// FieldTag.Raw, and to skip fields tagged "-".
+func NewFieldTagCacheMulti[T any](tagNames ...string) (*StructTagCache[T], error) {
+	if len(tagNames) == 0 {
+		return nil, errors.New("FieldTagCache needs at least one tag name")
+	}
+	for _, name := range tagNames[1:] {
+		if name == EmptyTag {
+			return nil, errors.New("FieldTagCache needs a non empty tag name")
+		}
+	}
+	return NewFieldTagCacheWithOptions[T](tagNames[0], func(o *options) {
+		o.extraTagNames = tagNames[1:]
+	})
+}
+
+// NewFieldTagCacheWithOptions[T any] initializes a StructTagCache for type T that is configured
+// by the options given.
+func NewFieldTagCacheWithOptions[T any](tagName string, opts ...Option) (*StructTagCache[T], error) {
+	if tagName == EmptyTag {
+		// field.Tag.Get("") returns the entire struct tag, which is never what is intended
+		return nil, errors.New("FieldTagCache needs a non empty tag name")
+	}
+	return newStructTagCache[T](tagName, newOptions(opts))
+}
+
+// newStructTagCache[T any] reads the definition of type T with the options given and returns an
+// empty StructTagCache for it.
+func newStructTagCache[T any](tagName string, o options) (*StructTagCache[T], error) {
+	for rType, values := range o.stringerEnums {
+		enum, err := getEnum(rType, values, true)
+		if err != nil {
+			return nil, err
+		}
+		o.enums[rType] = enum
+	}
+	for rType, values := range o.exhaustiveEnums {
+		enum, err := getEnum(rType, values, false)
+		if err != nil {
+			return nil, err
+		}
+		o.enums[rType] = enum
+	}
 	defType := reflect.TypeOf(*new(T))
 	switch defType.Kind() {
 	case reflect.Struct:
 		break
 	case reflect.Pointer:
 		defType = defType.Elem()
-		if defType.Elem().Kind() == reflect.Struct {
+		if defType.Kind() == reflect.Struct {
 			break
 		}
 		fallthrough
 	default:
 		return nil, errors.New("FieldTagCache needs a struct type for initialization")
 	}
+	schema, err := getTagSchema(defType)
+	if err != nil {
+		return nil, err
+	}
+	if provider, ok := reflect.New(defType).Interface().(TagSchemaProvider); ok {
+		schema = mergeTagSchema(schema, provider.TagSchema())
+	}
 	hasName := false
-	structTagMap := make(map[string]StructTagOption)
+	hasTail := false
+	defaultTrue := make([][]int, 0)
+	names := make([]string, 0)
+	positional := make(map[int]string)
+	structTagMap := make(map[string][]StructTagOption)
 	requiredTags := make([]string, 0)
-	for i := 0; i < defType.NumField(); i++ {
-		field := defType.Field(i)
-		if field.PkgPath != "" && !field.Anonymous {
-			continue
+	for _, structTag := range schema {
+		if o.caseInsensitiveKeys {
+			structTag.Name = strings.ToLower(structTag.Name)
+			conflictsWith := make([]string, len(structTag.ConflictsWith))
+			for i, other := range structTag.ConflictsWith {
+				conflictsWith[i] = strings.ToLower(other)
+			}
+			structTag.ConflictsWith = conflictsWith
+			aliases := make([]string, len(structTag.Aliases))
+			for i, alias := range structTag.Aliases {
+				aliases[i] = strings.ToLower(alias)
+			}
+			structTag.Aliases = aliases
 		}
-		tags := field.Tag.Get(StructTagTag)
-		structTag := StructTagOption{FieldIndex: i}
-		opts := strings.Split(tags, ",")
-		for n, o := range append(opts, strings.ToLower(field.Name)) {
-			if n == 0 {
-				if o != "-" {
-					structTag.Name = o
-				}
-			} else if n != len(opts) {
-				if o == RequiredTag {
-					structTag.Required = true
-				}
+		indexType := defType
+		for _, index := range structTag.fieldIndex() {
+			if indexType.Kind() != reflect.Struct || index < 0 || index >= indexType.NumField() {
+				return nil, fmt.Errorf("invalid field index %v for struct tag: %s", structTag.fieldIndex(), structTag.Name)
 			}
+			indexType = indexType.Field(index).Type
 		}
-		if structTag.Name != EmptyTag && structTag.Name != SkipTag {
-			fieldKind := field.Type.Kind()
-			if fieldKind == reflect.Slice {
+		field := defType.FieldByIndex(structTag.fieldIndex())
+		structTag.numeric = isNumericType(field.Type)
+		if structTag.Resolver == nil {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				// pointers are parsed by their underlying type
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Map && isMapKeyKind(fieldType.Key().Kind()) {
+				// maps are parsed by their value type as long as their keys are simple values
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Slice {
 				// just check for a 1d array, multidimensional arrays are not ideal for structtags imo
 				// and just wont be supported unless users decide to create their own resolvers
-				fieldKind = field.Type.Elem().Kind()
+				fieldType = fieldType.Elem()
 			}
-			switch fieldKind {
+			switch fieldType.Kind() {
 			case reflect.Slice, reflect.Array, reflect.Chan, reflect.Func, reflect.Interface, reflect.Invalid, reflect.Map, reflect.UnsafePointer:
 				// im unwilling to try to support the above types, so only solution is to create a custom resolver
 				// over a "raw" string value
-				if !field.Type.Implements(reflect.TypeOf((*StructTagOptionUnmarshaler)(nil)).Elem()) {
-					return nil, fmt.Errorf("unsupported type for struct tag: %s", field.Type)
+				if fieldType.Kind() == reflect.Interface && o.discriminator != EmptyTag {
+					break
+				}
+				_, registered := getRegisteredResolver(field.Type)
+				if _, ok := getRegisteredResolver(fieldType); ok {
+					// slices (and maps) of registered types are parsed by the registered resolver
+					registered = true
+				}
+				if !registered && !field.Type.Implements(reflect.TypeOf((*StructTagOptionUnmarshaler)(nil)).Elem()) &&
+					!field.Type.Implements(reflect.TypeOf((*MultiFieldUnmarshaler)(nil)).Elem()) {
+					return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, field.Type)
+				}
+			}
+			if structTag.Unit != EmptyTag {
+				if _, err := time.ParseDuration("1" + structTag.Unit); err != nil {
+					return nil, fmt.Errorf("unknown duration unit '%s' for struct tag: %s", structTag.Unit, structTag.Name)
+				}
+			}
+			if structTag.FlagSet != EmptyTag {
+				if _, ok := o.flagSets[structTag.FlagSet]; !ok {
+					return nil, fmt.Errorf("unknown flag set '%s' for struct tag: %s", structTag.FlagSet, structTag.Name)
 				}
 			}
-			if structTag.Name == NameTag {
-				hasName = true
+			if (structTag.FlagSet != EmptyTag || structTag.ByteSize) && !isIntegerKind(fieldType.Kind()) {
+				return nil, fmt.Errorf("flagset and bytesize can only be used with integer types for struct tag: %s", structTag.Name)
+			}
+			if structTag.Percent && fieldType.Kind() != reflect.Float32 && fieldType.Kind() != reflect.Float64 {
+				return nil, fmt.Errorf("percent can only be used with float types for struct tag: %s", structTag.Name)
+			}
+			if structTag.Path && fieldType.Kind() != reflect.String {
+				return nil, fmt.Errorf("path can only be used with string types for struct tag: %s", structTag.Name)
+			}
+			if structTag.Layout != EmptyTag && fieldType != reflect.TypeOf(time.Time{}) {
+				return nil, fmt.Errorf("layout can only be used with time.Time types for struct tag: %s", structTag.Name)
 			}
-			structTag.Resolver = getResolver(field.Type, structTag.Name)
-			if _, ok := structTagMap[structTag.Name]; ok {
-				return nil, errors.New("tag '" + structTag.Name + "' is in use by multiple fields")
+			structTag.Resolver = getResolver(field.Type, structTag, &o)
+		}
+		if structTag.DefaultTrue && field.Type.Kind() != reflect.Bool {
+			return nil, fmt.Errorf("defaulttrue can only be used with bool types for struct tag: %s", structTag.Name)
+		}
+		if structTag.MinLen > 0 || structTag.MaxLen > 0 {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
 			}
-			structTagMap[structTag.Name] = structTag
-			if structTag.Required {
-				requiredTags = append(requiredTags, structTag.Name)
+			if fieldType.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("minlen and maxlen can only be used with slice types for struct tag: %s", structTag.Name)
+			}
+		}
+		if structTag.Min != nil || structTag.Max != nil {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if !isIntegerKind(fieldType.Kind()) && fieldType.Kind() != reflect.Float32 && fieldType.Kind() != reflect.Float64 {
+				return nil, fmt.Errorf("min and max can only be used with integer and float types for struct tag: %s", structTag.Name)
+			}
+			if structTag.Min != nil && structTag.Max != nil && *structTag.Min > *structTag.Max {
+				return nil, fmt.Errorf("min is greater than max for struct tag: %s", structTag.Name)
+			}
+		}
+		if len(structTag.AllowedValues) > 0 {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() != reflect.String && !isIntegerKind(fieldType.Kind()) {
+				return nil, fmt.Errorf("enum can only be used with string and integer types for struct tag: %s", structTag.Name)
+			}
+			for _, allowed := range structTag.AllowedValues {
+				if _, err := convertToValue(allowed, fieldType.Kind()); err != nil {
+					return nil, fmt.Errorf("invalid enum value '%s' for struct tag: %s", allowed, structTag.Name)
+				}
 			}
 		}
+		if structTag.Name == NameTag {
+			hasName = true
+			structTag.Positional = true
+			structTag.Position = 0
+		}
+		if structTag.Name == TailTag {
+			if field.Type.Kind() != reflect.String {
+				return nil, fmt.Errorf("%s can only be used with string types", TailTag)
+			}
+			if _, ok := o.tokenizer.(DefaultTokenizer); !ok {
+				return nil, fmt.Errorf("%s can only be used with the DefaultTokenizer", TailTag)
+			}
+			hasTail = true
+		}
+		if structTag.Positional {
+			if name, ok := positional[structTag.Position]; ok && name != structTag.Name {
+				return nil, fmt.Errorf("position %d is in use by multiple fields", structTag.Position)
+			}
+			positional[structTag.Position] = structTag.Name
+		}
+		if structTag.Transform != EmptyTag {
+			transform, ok := o.transforms[structTag.Transform]
+			if !ok {
+				return nil, fmt.Errorf("unknown transform '%s' for struct tag: %s", structTag.Transform, structTag.Name)
+			}
+			structTag.transform = transform
+		}
+		structTag.scalar = structTag.transform == nil && o.conversionHook == nil && structTag.Min == nil && structTag.Max == nil &&
+			len(structTag.AllowedValues) == 0 && isScalarResolver(structTag.Resolver)
+		if _, ok := structTagMap[structTag.Name]; ok && !o.allowDuplicateNames {
+			return nil, &duplicateTagError{name: structTag.Name}
+		}
+		if _, ok := structTagMap[structTag.Name]; !ok {
+			names = append(names, structTag.Name)
+		}
+		structTagMap[structTag.Name] = append(structTagMap[structTag.Name], structTag)
+		if structTag.Required {
+			requiredTags = append(requiredTags, structTag.Name)
+		}
+		if structTag.DefaultTrue {
+			defaultTrue = append(defaultTrue, structTag.fieldIndex())
+		}
+	}
+	aliases := make(map[string]string)
+	for _, name := range names {
+		for _, st := range structTagMap[name] {
+			for _, alias := range st.Aliases {
+				_, isName := structTagMap[alias]
+				if other, isAlias := aliases[alias]; isName || (isAlias && other != name) {
+					return nil, &duplicateTagError{name: alias}
+				}
+				aliases[alias] = name
+			}
+		}
+	}
+	conflicts := make(map[string][]string)
+	for _, name := range names {
+		for _, st := range structTagMap[name] {
+			for _, other := range st.ConflictsWith {
+				if _, ok := structTagMap[other]; !ok {
+					return nil, fmt.Errorf("unknown conflicting option '%s' for struct tag: %s", other, name)
+				}
+				conflicts[name] = append(conflicts[name], other)
+				conflicts[other] = append(conflicts[other], name)
+			}
+		}
+	}
+	tailStart := 0
+	for position := range positional {
+		if position >= tailStart {
+			tailStart = position + 1
+		}
 	}
 	return &StructTagCache[T]{
 		tagName:      tagName,
 		typeToTags:   make(map[reflect.Type][]FieldTag[T]),
+		nameToTypes:  make(map[string][]reflect.Type),
 		structTagMap: structTagMap,
+		names:        names,
+		aliases:      aliases,
+		conflicts:    conflicts,
 		hasName:      hasName,
+		hasTail:      hasTail,
+		tailStart:    tailStart,
+		defaultTrue:  defaultTrue,
+		positional:   positional,
 		requiredTags: requiredTags,
+		options:      o,
 	}, nil
 }
 
-func getNextTagValue(tag string) (string, string, error) {
-	valueStr := ""
-	var kv []int
-	if tag != EmptyTag && tag[0] == '\'' {
-		tag = tag[1:]
-		for {
-			kv = untilNextQuoteRegex.FindStringSubmatchIndex(tag)
-			if kv == nil {
-				return "", "", errors.New("missing end quote on quoted string")
+// getTagSchema reflects over the fields of a definition struct and returns the options described
+// by their `structtag` tags. Embedded structs without a `structtag` tag are reflected over as well
+// so that definition structs can be composed of groups of options.
+func getTagSchema(defType reflect.Type) ([]StructTagOption, error) {
+	return appendTagSchema(make([]StructTagOption, 0), defType, nil)
+}
+
+// appendTagSchema appends the options described by the fields of a struct embedded in the
+// definition struct at the index sequence given (or of the definition struct itself if it is
+// empty) to a schema.
+func appendTagSchema(schema []StructTagOption, defType reflect.Type, index []int) ([]StructTagOption, error) {
+	for i := 0; i < defType.NumField(); i++ {
+		field := defType.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tags, ok := field.Tag.Lookup(StructTagTag)
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		if !ok && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			var err error
+			if schema, err = appendTagSchema(schema, field.Type, fieldIndex); err != nil {
+				return nil, err
 			}
-			valueStr += tag[kv[2]:kv[3]]
-			if kv[3] > 0 && kv[3] > kv[2] && tag[kv[3]-1] == '\\' {
-				valueStr = valueStr[:len(valueStr)-1] + "'"
-				tag = tag[kv[1]:]
-			} else {
-				break
+			continue
+		}
+		structTag := StructTagOption{FieldIndex: fieldIndex[0]}
+		if len(fieldIndex) > 1 {
+			structTag.FieldIndexPath = fieldIndex
+		}
+		opts := strings.Split(tags, ",")
+		if opts[0] != SkipTag {
+			structTag.Name = opts[0]
+		}
+		for _, opt := range opts[1:] {
+			key, value, _ := strings.Cut(opt, "=")
+			switch key {
+			case RequiredTag:
+				structTag.Required = true
+			case TransformTag:
+				structTag.Transform = value
+			case UnitTag:
+				structTag.Unit = value
+			case PathTag:
+				structTag.Path = true
+			case PercentTag:
+				structTag.Percent = true
+			case ByteSizeTag:
+				structTag.ByteSize = true
+			case FlagSetTag:
+				structTag.FlagSet = value
+			case DefaultTrueTag:
+				structTag.DefaultTrue = true
+			case DefaultTag:
+				structTag.Default = value
+			case ConflictsWithTag:
+				structTag.ConflictsWith = strings.Split(value, "|")
+			case LayoutTag:
+				structTag.Layout = value
+			case MinLenTag, MaxLenTag:
+				length, err := strconv.Atoi(value)
+				if err != nil || length < 0 {
+					return nil, fmt.Errorf("invalid %s '%s' for struct tag: %s", key, value, structTag.Name)
+				}
+				if key == MinLenTag {
+					structTag.MinLen = length
+				} else {
+					structTag.MaxLen = length
+				}
+			case PositionalTag:
+				position, err := strconv.Atoi(value)
+				if err != nil || position < 0 {
+					return nil, fmt.Errorf("invalid position '%s' for struct tag: %s", value, structTag.Name)
+				}
+				structTag.Positional = true
+				structTag.Position = position
 			}
 		}
-		if kv != nil {
-			tag = tag[kv[1]:]
+		if value, ok := field.Tag.Lookup(DefaultTag); ok && structTag.Default == EmptyTag {
+			structTag.Default = value
+		}
+		if value, ok := field.Tag.Lookup(LayoutTag); ok && structTag.Layout == EmptyTag {
+			structTag.Layout = value
+		}
+		if value, ok := field.Tag.Lookup(AliasTag); ok && value != EmptyTag {
+			structTag.Aliases = strings.Split(value, ",")
+		}
+		if value, ok := field.Tag.Lookup(EnumTag); ok && value != EmptyTag {
+			structTag.AllowedValues = strings.Split(value, ",")
+		}
+		var err error
+		if structTag.Min, err = parseBound(field, MinTag, structTag.Name); err != nil {
+			return nil, err
+		}
+		if structTag.Max, err = parseBound(field, MaxTag, structTag.Name); err != nil {
+			return nil, err
+		}
+		if structTag.Name != EmptyTag && structTag.Name != SkipTag {
+			schema = append(schema, structTag)
 		}
-	} else {
-		kv = untilNextCommaRegex.FindStringSubmatchIndex(tag)
-		valueStart, valueEnd := kv[2], kv[3]
-		valueStr = strings.Replace(tag[valueStart:valueEnd], `\'`, `'`, -1)
-		tag = tag[kv[1]:]
 	}
-	return tag, valueStr, nil
+	return schema, nil
+}
+
+// parseBound parses the min or max struct tag of a field in the definition struct. It returns nil
+// if the field does not have the tag.
+func parseBound(field reflect.StructField, key string, name string) (*float64, error) {
+	value, ok := field.Tag.Lookup(key)
+	if !ok {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s '%s' for struct tag: %s", key, value, name)
+	}
+	return &parsed, nil
+}
+
+// mergeTagSchema replaces any options in the reflected schema that are for the same field as
+// an option in the provided schema.
+func mergeTagSchema(reflected []StructTagOption, provided []StructTagOption) []StructTagOption {
+	providedFields := make(map[string]struct{})
+	for _, opt := range provided {
+		providedFields[fmt.Sprint(opt.fieldIndex())] = struct{}{}
+	}
+	schema := make([]StructTagOption, 0, len(reflected)+len(provided))
+	for _, opt := range reflected {
+		if _, ok := providedFields[fmt.Sprint(opt.fieldIndex())]; !ok {
+			schema = append(schema, opt)
+		}
+	}
+	return append(schema, provided...)
 }
 
 func (t *StructTagCache[T]) actualType(rType reflect.Type) reflect.Type {
@@ -241,133 +743,1035 @@ func (t *StructTagCache[T]) actualType(rType reflect.Type) reflect.Type {
 // returning any validation errors found.
 func (t *StructTagCache[T]) Add(rType reflect.Type) error {
 	rType = t.actualType(rType)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	parsed, err := t.parseNested(rType)
+	if err != nil {
+		return err
+	}
+	t.storeAll(parsed)
+	return nil
+}
+
+// AddWithOptions is the same as Add but parses the type with options layered over the options the
+// cache was created with (i.e. WithStrictBareValues for a single type). The definition of T is read
+// again with the layered options, so every option applies the same as if the cache had been created
+// with it, and options made required (or not) by SetRequired stay that way.
+func (t *StructTagCache[T]) AddWithOptions(rType reflect.Type, opts ...Option) error {
+	rType = t.actualType(rType)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	layered, err := newStructTagCache[T](t.tagName, t.options.layer(opts))
+	if err != nil {
+		return err
+	}
+	for _, name := range t.names {
+		layeredOpts := layered.structTagMap[layered.optionName(name)]
+		for i := range layeredOpts {
+			if i < len(t.structTagMap[name]) {
+				layeredOpts[i].Required = t.structTagMap[name][i].Required
+			}
+		}
+	}
+	layered.requiredTags = make([]string, len(t.requiredTags))
+	for i, name := range t.requiredTags {
+		layered.requiredTags[i] = layered.optionName(name)
+	}
+	// types that are already cached are not parsed again by WithRecursive
+	layered.typeToTags = t.typeToTags
+	parsed, err := layered.parseNested(rType)
+	if err != nil {
+		return err
+	}
+	t.storeAll(parsed)
+	return nil
+}
+
+// AddValue parses the struct tags from the type of the value given (i.e. a struct or a pointer to
+// one) and adds them to the internal cache while returning any validation errors found.
+func (t *StructTagCache[T]) AddValue(v any) error {
+	if v == nil {
+		return errors.New("FieldTagCache cannot cache the type of a nil value")
+	}
+	return t.Add(reflect.TypeOf(v))
+}
+
+// AddReflectValue is the same as AddValue for a reflect.Value, which avoids boxing values that are
+// already held as a reflect.Value (i.e. in reflection heavy loops). Interface values are parsed by
+// the type of the value they hold.
+func (t *StructTagCache[T]) AddReflectValue(v reflect.Value) error {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return errors.New("FieldTagCache cannot cache the type of a nil value")
+	}
+	return t.Add(v.Type())
+}
+
+// store adds the parsed struct tags for a type to the internal cache. The caller must hold the
+// write lock.
+func (t *StructTagCache[T]) store(rType reflect.Type, fieldTags []FieldTag[T]) {
+	if _, ok := t.typeToTags[rType]; !ok {
+		name := typeName(rType)
+		t.nameToTypes[name] = append(t.nameToTypes[name], rType)
+	}
+	t.typeToTags[rType] = fieldTags
+}
+
+// storeAll adds the parsed struct tags for every type given to the internal cache. The caller must
+// hold the write lock.
+func (t *StructTagCache[T]) storeAll(parsed map[reflect.Type][]FieldTag[T]) {
+	for rType, fieldTags := range parsed {
+		t.store(rType, fieldTags)
+	}
+}
+
+// remove deletes the parsed struct tags for a type from the internal cache. The caller must hold
+// the write lock.
+func (t *StructTagCache[T]) remove(rType reflect.Type) {
+	if _, ok := t.typeToTags[rType]; !ok {
+		return
+	}
+	delete(t.typeToTags, rType)
+	name := typeName(rType)
+	types := make([]reflect.Type, 0, len(t.nameToTypes[name]))
+	for _, other := range t.nameToTypes[name] {
+		if other != rType {
+			types = append(types, other)
+		}
+	}
+	if len(types) == 0 {
+		delete(t.nameToTypes, name)
+	} else {
+		t.nameToTypes[name] = types
+	}
+}
+
+// typeName returns the package path qualified name of a type (i.e. github.com/user/pkg.Type).
+func typeName(rType reflect.Type) string {
+	if rType.PkgPath() == EmptyTag {
+		return rType.String()
+	}
+	return rType.PkgPath() + "." + rType.Name()
+}
+
+// ValidateType parses the struct tags from the type given and returns any validation errors found
+// without adding them to the internal cache.
+func (t *StructTagCache[T]) ValidateType(rType reflect.Type) error {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	_, err := t.parseType(t.actualType(rType))
+	return err
+}
+
+// parseType parses the struct tags for every field of a struct type. The caller must hold the
+// read or write lock.
+func (t *StructTagCache[T]) parseType(rType reflect.Type) ([]FieldTag[T], error) {
+	if t.options.allowedTypes != nil && !t.options.allowedTypes(rType) {
+		return nil, fmt.Errorf("type is not allowed: %s", rType)
+	}
 	kind := rType.Kind()
 	if kind != reflect.Struct {
-		return errors.New("FieldTagCache cannot cache non struct types")
+		return nil, errors.New("FieldTagCache cannot cache non struct types")
 	}
 
-	var field reflect.StructField
-	var tag string
-	var key string
-	var valueStr string
-	var err error
-	fieldTags := make([]FieldTag[T], 0)
+	return t.appendFieldTags(make([]FieldTag[T], 0), rType, nil, map[reflect.Type]struct{}{rType: {}})
+}
+
+// parseNested parses the struct tags for a type and, if the cache was created WithRecursive, for
+// every struct type (or slice, array, or pointer of one) used by its fields that is not cached yet.
+// Nothing is returned unless every type parses. The caller must hold the read or write lock.
+func (t *StructTagCache[T]) parseNested(rType reflect.Type) (map[reflect.Type][]FieldTag[T], error) {
+	parsed := make(map[reflect.Type][]FieldTag[T])
+	if err := t.appendNested(parsed, rType); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// appendNested parses the struct tags for a type (and the types nested in it) into parsed.
+func (t *StructTagCache[T]) appendNested(parsed map[reflect.Type][]FieldTag[T], rType reflect.Type) error {
+	fieldTags, err := t.parseType(rType)
+	if err != nil {
+		return err
+	}
+	parsed[rType] = fieldTags
+	if !t.options.recursive {
+		return nil
+	}
+	for _, tag := range fieldTags {
+		nested := t.actualType(tag.FieldType)
+		if _, ok := parsed[nested]; ok || !hasExportedFields(nested) {
+			continue
+		}
+		if _, ok := t.typeToTags[nested]; ok {
+			continue
+		}
+		if err := t.appendNested(parsed, nested); err != nil {
+			return fmt.Errorf("unable to parse nested type %s of struct field %s: %w", nested, tag.FieldName, err)
+		}
+	}
+	return nil
+}
+
+// hasExportedFields returns whether or not a type is a struct with exported fields. Structs without
+// them (i.e. time.Time) have no struct tags to parse.
+func hasExportedFields(rType reflect.Type) bool {
+	if rType.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < rType.NumField(); i++ {
+		if rType.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// appendFieldTags parses the struct tags for every field of a struct type embedded at the index
+// sequence given (or of the type itself if it is empty). Fields of embedded structs (or pointers to
+// them) without a tag are parsed in place of the field that embeds them, the same way encoding/json
+// promotes them, and fields tagged "-" are skipped. Embedded structs that are already being parsed
+// (i.e. a struct that embeds a pointer to itself) are skipped.
+func (t *StructTagCache[T]) appendFieldTags(fieldTags []FieldTag[T], rType reflect.Type, index []int, embedding map[reflect.Type]struct{}) ([]FieldTag[T], error) {
 	for i := 0; i < rType.NumField(); i++ {
-		field = rType.Field(i)
-		tag = field.Tag.Get(t.tagName)
-		if field.PkgPath != "" || field.Anonymous {
+		field := rType.Field(i)
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		embedded := field.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if !t.hasTag(field) && field.Anonymous && embedded.Kind() == reflect.Struct {
+			if _, ok := embedding[embedded]; ok {
+				continue
+			}
+			embedding[embedded] = struct{}{}
+			var err error
+			if fieldTags, err = t.appendFieldTags(fieldTags, embedded, fieldIndex, embedding); err != nil {
+				return nil, err
+			}
+			delete(embedding, embedded)
 			continue
 		}
-		value := new(T)
-		ft := FieldTag[T]{
-			FieldName:  field.Name,
-			FieldIndex: i,
+		if (field.PkgPath != "" && !t.options.unexported) || field.Anonymous {
+			continue
 		}
-		ftv := reflect.Indirect(reflect.ValueOf(value))
-		var v reflect.Value
-		requiredTags := make([]string, 0)
-		for i := 0; ; i++ {
-			valueStr = ""
-			kv := keyValueRegex.FindStringSubmatchIndex(tag)
-			if kv == nil {
-				break
-			}
-			keyStart, keyEnd, valueStart, valueEnd := kv[2], kv[3], kv[4], kv[5]
-			if keyEnd > 0 {
-				key = tag[keyStart:keyEnd]
-			} else {
-				key = ""
-			}
-			if valueEnd > 0 {
-				tag = tag[valueStart:valueEnd]
-				if tag[0] == '[' {
-					tag = tag[1:]
-					for {
-						kv = untilNextBracketRegex.FindStringSubmatchIndex(tag)
-						if kv == nil {
-							return errors.New("missing end quote on quoted string")
-						}
-						valueStr += tag[kv[2]:kv[3]]
-						if kv[3] > 0 && kv[3] > kv[2] && tag[kv[3]-1] == '\\' {
-							valueStr = valueStr[:len(valueStr)-1] + "]"
-							tag = tag[kv[1]:]
-						} else {
-							break
-						}
-					}
-					if kv != nil {
-						tag = tag[kv[1]:]
-					}
-				} else {
-					tag, valueStr, err = getNextTagValue(tag)
-					if err != nil {
-						return err
-					}
-				}
-				if i == 0 && t.hasName {
-					key = NameTag
-				} else if key == "" {
-					key = valueStr
-				}
-				if st, ok := t.structTagMap[key]; ok {
-					v, err = st.Resolver.UnmarshalTagOption(field, valueStr)
-					if err != nil {
-						if st.Required {
-							// may potentially want to allow for a not-found error to be checked or something?
-							return err
-						}
-					} else {
-						if !v.CanConvert(ftv.Field(st.FieldIndex).Type()) {
-							return fmt.Errorf("unable to convert value of '%s' to type '%s' for field '%s'", ftv.Type().Field(st.FieldIndex).Name, ftv.Field(st.FieldIndex).Type(), field.Name)
-						}
-						ftv.Field(st.FieldIndex).Set(v.Convert(ftv.Field(st.FieldIndex).Type()))
-						if st.Required {
-							requiredTags = append(requiredTags, st.Name)
-						}
-					}
+		if field.Tag.Get(t.tagName) == SkipTag {
+			// fields tagged "-" are left out entirely, the same way encoding/json ignores them
+			continue
+		}
+		ft, err := t.parseField(field, fieldIndex)
+		if err != nil {
+			return nil, err
+		}
+		fieldTags = append(fieldTags, ft)
+	}
+	return fieldTags, nil
+}
+
+// hasTag returns whether or not a field has a tag with any of the tag names of the cache.
+func (t *StructTagCache[T]) hasTag(field reflect.StructField) bool {
+	for _, name := range append([]string{t.tagName}, t.options.extraTagNames...) {
+		if _, ok := field.Tag.Lookup(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseField parses the struct tag of a single field into a FieldTag.
+func (t *StructTagCache[T]) parseField(field reflect.StructField, index []int) (FieldTag[T], error) {
+	ft := FieldTag[T]{
+		FieldName:      field.Name,
+		FieldIndex:     index[len(index)-1],
+		FieldIndexPath: index,
+		FieldType:      field.Type,
+	}
+	if t.options.descriptionTag != EmptyTag {
+		ft.Description = field.Tag.Get(t.options.descriptionTag)
+	}
+	tag := field.Tag.Get(t.tagName)
+	ft.Raw = tag
+	if t.isIgnored(tag) {
+		// ignored tags are not parsed at all, so the value is left as the zero value
+		return ft, nil
+	}
+	tokens, tail, err := t.tokenizeTag(field, tag)
+	if err != nil {
+		return ft, err
+	}
+	// positional options only claim values from the first tag name
+	primary := len(tokens)
+	// tagIndexes is the index of the tag name that each token was given in
+	tagIndexes := make([]int, len(tokens))
+	for tagIndex, name := range t.options.extraTagNames {
+		extra, ok := field.Tag.Lookup(name)
+		if !ok || t.isIgnored(extra) {
+			continue
+		}
+		extraTokens, extraTail, err := t.tokenizeTag(field, extra)
+		if err != nil {
+			return ft, err
+		}
+		tokens = append(tokens, extraTokens...)
+		for range extraTokens {
+			tagIndexes = append(tagIndexes, tagIndex+1)
+		}
+		if extraTail != EmptyTag {
+			tail = extraTail
+		}
+	}
+	value := new(T)
+	ftv := reflect.ValueOf(value).Elem()
+	if ftv.Kind() == reflect.Pointer {
+		// pointer definition types are parsed into a newly allocated struct
+		ftv.Set(reflect.New(ftv.Type().Elem()))
+		ftv = ftv.Elem()
+	}
+	requiredTags := make([]string, 0)
+	given := make(map[string]struct{})
+	givenIn := make(map[string]int)
+	set := make(map[string]struct{})
+	for _, index := range t.defaultTrue {
+		ftv.FieldByIndex(index).SetBool(true)
+	}
+	for i, token := range tokens {
+		if i == 0 && token.Key == EmptyTag && t.options.nameMetaSeparator != EmptyTag {
+			token.Value, ft.NameMeta, _ = strings.Cut(token.Value, t.options.nameMetaSeparator)
+		}
+		key := token.Key
+		if t.options.caseInsensitiveKeys {
+			key = strings.ToLower(key)
+		}
+		if name, ok := t.positional[i]; ok && i < primary && (name == NameTag || key == EmptyTag) {
+			key = name
+		} else if key == EmptyTag {
+			key = token.Value
+			if t.options.trimSpace {
+				key = strings.TrimSpace(key)
+			}
+			if t.options.caseInsensitiveKeys {
+				if lower := strings.ToLower(key); t.structTagMap[strings.TrimPrefix(lower, "!")] != nil || t.aliases[strings.TrimPrefix(lower, "!")] != EmptyTag {
+					// bare keys are given to bool resolvers as their value, so they need to match exactly
+					key = lower
+					token.Value = lower
 				}
-			} else {
-				break
+			}
+			if strings.HasPrefix(key, "!") && t.isBoolOption(key[1:]) {
+				// !key disables a bool option
+				key = key[1:]
+				token.Value = "false"
 			}
 		}
-		if len(requiredTags) != len(t.requiredTags) {
-			requiredMap := make(map[string]struct{})
-			for _, r := range t.requiredTags {
-				requiredMap[r] = struct{}{}
+		if name, ok := t.aliases[key]; ok {
+			if token.Key == EmptyTag && token.Value == key {
+				// bare keys are given to bool resolvers as their value, so they need to match exactly
+				token.Value = name
 			}
-			for _, r := range requiredTags {
-				delete(requiredMap, r)
+			key = name
+		}
+		opts, ok := t.structTagMap[key]
+		if !ok && token.Key != EmptyTag && t.options.strictKeys {
+			return ft, fmt.Errorf("unknown option key '%s' for struct field: %s", token.Key, field.Name)
+		}
+		if !ok && token.Key == EmptyTag {
+			// bare values that do not match an option are given to the first positional option
+			// that has not been given yet (only from the first tag name, the same as positions)
+			if i < primary {
+				key = t.nextPositional(given)
 			}
-			requiredTags := make([]string, 0)
-			for r := range requiredMap {
-				requiredTags = append(requiredTags, r)
+			if opts, ok = t.structTagMap[key]; !ok && t.options.strictBareValues {
+				return ft, fmt.Errorf("unknown option '%s' for struct field: %s", token.Value, field.Name)
+			} else if !ok {
+				key = token.Value
 			}
-			return fmt.Errorf("missing required tag fields: %s for struct field: %s", requiredTags, field.Name)
 		}
-		ft.Value = *value
-		fieldTags = append(fieldTags, ft)
+		// keys repeated within the same tag name append to slice options, while later tag names
+		// override earlier ones
+		_, repeated := given[key]
+		repeated = repeated && givenIn[key] == tagIndexes[i]
+		given[key] = struct{}{}
+		givenIn[key] = tagIndexes[i]
+		scanned := TagToken{Key: key, Value: token.Value}
+		for _, st := range opts {
+			var previous reflect.Value
+			if fv := ftv.FieldByIndex(st.fieldIndex()); repeated && fv.Kind() == reflect.Slice {
+				previous = reflect.ValueOf(fv.Interface())
+				// the scanned value is joined the same way so that GetRaw matches the parsed value
+				scanned.Value = lastScanned(ft.scanned, key) + t.options.separator + token.Value
+			}
+			value := token.Value
+			if token.escaped != EmptyTag && isListResolver(st.Resolver) {
+				// lists are split by their resolver, which also removes the escapes
+				value = token.escaped
+			} else if token.bracket != 0 && isBracketedResolver(st.Resolver) {
+				// raw JSON and maps are given their brackets back so that they see the value as written
+				value = string(token.bracket) + value + string(closingBracket(token.bracket))
+			}
+			isSet, err := t.setOption(ftv, field, st, value, previous)
+			if err != nil {
+				return ft, err
+			}
+			if isSet {
+				set[key] = struct{}{}
+			}
+			if isSet && st.Required {
+				requiredTags = append(requiredTags, st.Name)
+			}
+		}
+		ft.scanned = append(ft.scanned, scanned)
+	}
+	if tail != EmptyTag {
+		ft.scanned = append(ft.scanned, TagToken{Key: TailTag, Value: tail})
+		given[TailTag] = struct{}{}
+		for _, st := range t.structTagMap[TailTag] {
+			isSet, err := t.setOption(ftv, field, st, tail, reflect.Value{})
+			if err != nil {
+				return ft, err
+			}
+			if isSet && st.Required {
+				requiredTags = append(requiredTags, st.Name)
+			}
+		}
+	}
+	for _, name := range t.names {
+		if _, ok := given[name]; !ok {
+			continue
+		}
+		for _, other := range t.conflicts[name] {
+			if _, ok := given[other]; ok {
+				return ft, fmt.Errorf("options '%s' and '%s' conflict for struct field: %s", name, other, field.Name)
+			}
+		}
+	}
+	provider, hasProvider := ftv.Addr().Interface().(DefaultProvider)
+	for _, name := range t.names {
+		if _, ok := given[name]; ok {
+			continue
+		}
+		for _, st := range t.structTagMap[name] {
+			defaultStr, ok := st.Default, st.Default != EmptyTag
+			if !ok && hasProvider {
+				defaultStr, ok = provider.DefaultFor(name)
+			}
+			if !ok {
+				continue
+			}
+			isSet, err := t.setOption(ftv, field, st, defaultStr, reflect.Value{})
+			if err != nil {
+				return ft, err
+			}
+			if isSet {
+				set[name] = struct{}{}
+			}
+			if isSet && st.Required {
+				requiredTags = append(requiredTags, st.Name)
+			}
+		}
+	}
+	for _, name := range t.names {
+		if _, ok := set[name]; !ok {
+			continue
+		}
+		for _, st := range t.structTagMap[name] {
+			if err := st.validateLength(ftv.FieldByIndex(st.fieldIndex())); err != nil {
+				return ft, fmt.Errorf("%w for struct field: %s", err, field.Name)
+			}
+		}
+	}
+	requiredMap := make(map[string]struct{})
+	for _, r := range t.requiredTags {
+		requiredMap[r] = struct{}{}
+	}
+	for _, r := range requiredTags {
+		delete(requiredMap, r)
+	}
+	if len(requiredMap) > 0 {
+		requiredTags := make([]string, 0)
+		for r := range requiredMap {
+			requiredTags = append(requiredTags, r)
+		}
+		return ft, fmt.Errorf("%w: %s for struct field: %s", ErrMissingRequired, requiredTags, field.Name)
+	}
+	ft.Value = *value
+	return ft, nil
+}
+
+// isIgnored returns whether or not a tag starts with the prefix given to WithIgnorePrefix.
+func (t *StructTagCache[T]) isIgnored(tag string) bool {
+	return t.options.ignorePrefix != EmptyTag && strings.HasPrefix(tag, t.options.ignorePrefix)
+}
+
+// tokenizeTag splits a tag into tokens (and the $tail value if T has one) after selecting the
+// profile and expanding templates.
+func (t *StructTagCache[T]) tokenizeTag(field reflect.StructField, tag string) ([]TagToken, string, error) {
+	var tokens []TagToken
+	var tail string
+	var err error
+	if t.options.profile != EmptyTag {
+		if tag, err = selectProfile(tag, t.options.profile); err != nil {
+			return nil, EmptyTag, err
+		}
+	}
+	if t.hasTail {
+		tokens, tail, err = splitTail(tag, t.tailStart, t.options.separator, t.options.decodeEscapes)
+	} else {
+		tokens, err = t.tokenize(tag)
+	}
+	if err != nil {
+		return nil, EmptyTag, err
+	}
+	if len(t.options.templates) > 0 {
+		if tokens, err = t.expandTemplates(tokens, make(map[string]struct{})); err != nil {
+			return nil, EmptyTag, fmt.Errorf("%w for struct field: %s", err, field.Name)
+		}
+	}
+	return tokens, tail, nil
+}
+
+// tokenize splits a tag into tokens with the tokenizer of the cache. Escaped backslashes are kept
+// by the DefaultTokenizer if the cache was created WithDecodeEscapes so that they are only decoded
+// once.
+func (t *StructTagCache[T]) tokenize(tag string) ([]TagToken, error) {
+	if d, ok := t.options.tokenizer.(DefaultTokenizer); ok && t.options.decodeEscapes {
+		tokens, _, err := splitTail(tag, -1, d.separator(), true)
+		return tokens, err
+	}
+	return t.options.tokenizer.Tokenize(tag)
+}
+
+// TemplatePrefix is the prefix of values that reference a template registered with WithTemplate
+// (i.e. `@defaults`).
+const TemplatePrefix = "@"
+
+// expandTemplates replaces tokens that reference a registered template. Values given without a key
+// are replaced by the options of the template while values given to a key are replaced by the
+// template as is. Templates that reference themselves (directly or not) return an error.
+func (t *StructTagCache[T]) expandTemplates(tokens []TagToken, expanding map[string]struct{}) ([]TagToken, error) {
+	expanded := make([]TagToken, 0, len(tokens))
+	for _, token := range tokens {
+		name := strings.TrimPrefix(token.Value, TemplatePrefix)
+		template, ok := t.options.templates[name]
+		if !ok || name == token.Value {
+			expanded = append(expanded, token)
+			continue
+		}
+		if _, ok := expanding[name]; ok {
+			return nil, fmt.Errorf("template '%s' references itself", name)
+		}
+		expanding[name] = struct{}{}
+		templateTokens := []TagToken{{Key: token.Key, Value: template}}
+		if token.Key == EmptyTag {
+			var err error
+			if templateTokens, err = t.tokenize(template); err != nil {
+				return nil, fmt.Errorf("invalid template '%s': %w", name, err)
+			}
+		}
+		templateTokens, err := t.expandTemplates(templateTokens, expanding)
+		if err != nil {
+			return nil, err
+		}
+		delete(expanding, name)
+		expanded = append(expanded, templateTokens...)
+	}
+	return expanded, nil
+}
+
+// isBoolOption returns whether or not every option with a name is a bool (or bool pointer) option.
+func (t *StructTagCache[T]) isBoolOption(name string) bool {
+	if alias, ok := t.aliases[name]; ok {
+		name = alias
+	}
+	opts, ok := t.structTagMap[name]
+	for _, st := range opts {
+		resolver := st.Resolver
+		if pointer, isPointer := resolver.(*pointerResolver); isPointer {
+			resolver = pointer.resolver
+		}
+		if _, isBool := resolver.(*boolResolver); !isBool {
+			return false
+		}
+	}
+	return ok
+}
+
+// nextPositional returns the name of the positional option with the lowest position that has not
+// been given yet (or an empty string if there are none).
+func (t *StructTagCache[T]) nextPositional(given map[string]struct{}) string {
+	positions := make([]int, 0, len(t.positional))
+	for position := range t.positional {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+	for _, position := range positions {
+		if _, ok := given[t.positional[position]]; !ok {
+			return t.positional[position]
+		}
+	}
+	return EmptyTag
+}
+
+// SecretPrefix is the prefix of values that are resolved by the provider given by
+// WithSecretProvider (i.e. password=secret:db_password).
+const SecretPrefix = "secret:"
+
+// ErrMissingRequired is returned when a struct tag does not give a value for a required option.
+var ErrMissingRequired = errors.New("missing required tag fields")
+
+// ErrUnsupportedType is returned when a definition struct has an option of a type that can not be
+// parsed without a custom resolver.
+var ErrUnsupportedType = errors.New("unsupported type for struct tag")
+
+// ErrDuplicateTagName is returned when multiple fields of a definition struct use the same option
+// name and the cache was not created WithAllowDuplicateNames.
+var ErrDuplicateTagName = errors.New("tag is in use by multiple fields")
+
+// duplicateTagError keeps the name of the duplicated option in the message while still matching
+// ErrDuplicateTagName with errors.Is.
+type duplicateTagError struct {
+	name string
+}
+
+func (d *duplicateTagError) Error() string {
+	return "tag '" + d.name + "' is in use by multiple fields"
+}
+
+func (d *duplicateTagError) Is(target error) bool {
+	return target == ErrDuplicateTagName
+}
+
+// ConversionError is returned when the value of a required option could not be converted to the
+// type of its field in the definition struct. The message is the same as the error it wraps.
+type ConversionError struct {
+	// FieldName is the name of the field whose struct tag was being parsed.
+	FieldName string
+	// Option is the name of the option (or definition struct field) being set.
+	Option string
+	// TargetType is the type of the definition struct field the value was converted to.
+	TargetType reflect.Type
+	// Value is the raw value given to the option (if any).
+	Value string
+	// Err is the error returned by the resolver or conversion.
+	Err error
+}
+
+func (c *ConversionError) Error() string {
+	return c.Err.Error()
+}
+
+func (c *ConversionError) Unwrap() error {
+	return c.Err
+}
+
+// ErrResolverTimeout is returned when a resolver takes longer than the timeout given by
+// WithResolverTimeout. It is returned even if the option is not required.
+var ErrResolverTimeout = errors.New("resolver timed out")
+
+// ErrResolverPanic is returned when a resolver panics and the cache was created WithRecoverPanics.
+// It is returned even if the option is not required.
+var ErrResolverPanic = errors.New("resolver panicked")
+
+// callResolver calls a resolver and returns ErrResolverTimeout if a timeout was configured and the
+// resolver takes longer than it. Resolvers that time out cannot be stopped, so they are left to
+// finish in the background and their result is discarded. If panics are recovered, a panic is
+// returned as ErrResolverPanic.
+func (t *StructTagCache[T]) callResolver(field reflect.StructField, st StructTagOption, resolve func() error) error {
+	if t.options.recoverPanics {
+		unsafeResolve := resolve
+		resolve = func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%w for option '%s' for struct field %s: %v", ErrResolverPanic, st.Name, field.Name, r)
+				}
+			}()
+			return unsafeResolve()
+		}
+	}
+	if t.options.resolverTimeout <= 0 {
+		return resolve()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- resolve()
+	}()
+	timer := time.NewTimer(t.options.resolverTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("%w after %s for option: %s", ErrResolverTimeout, t.options.resolverTimeout, st.Name)
+	}
+}
+
+// optionError returns the error from resolving an option as a ConversionError if it is required.
+// Otherwise the error is ignored and the value is passed to the unknown value marker if one was
+// configured.
+func (t *StructTagCache[T]) optionError(ftv reflect.Value, field reflect.StructField, st StructTagOption, valueStr string, err error) error {
+	if st.Required {
+		return &ConversionError{
+			FieldName:  field.Name,
+			Option:     st.Name,
+			TargetType: ftv.Type().FieldByIndex(st.fieldIndex()).Type,
+			Value:      valueStr,
+			Err:        err,
+		}
+	}
+	if t.options.unknownValueMarker != nil {
+		t.options.unknownValueMarker(field, valueStr)
 	}
-	t.typeToTags[rType] = fieldTags
 	return nil
 }
 
+// recoverOption sets the value returned by the error recovery function (if one was configured and
+// it returns true) in place of a value that failed to resolve. The value is validated the same as
+// a resolved value. Otherwise the error is handled by optionError.
+func (t *StructTagCache[T]) recoverOption(ftv reflect.Value, field reflect.StructField, st StructTagOption, valueStr string, err error) (bool, error) {
+	if t.options.errorRecovery != nil {
+		if v, ok := t.options.errorRecovery(field, st.Name, valueStr, err); ok {
+			if err := setValue(ftv, st.fieldIndex(), v, field); err != nil {
+				return false, err
+			}
+			if err := st.validateValue(ftv.FieldByIndex(st.fieldIndex())); err != nil {
+				return false, fmt.Errorf("%w for struct field: %s", err, field.Name)
+			}
+			return true, nil
+		}
+	}
+	return false, t.optionError(ftv, field, st, valueStr, err)
+}
+
+// trimBrackets removes the brackets (or braces) around a list if there are any.
+func trimBrackets(value string) string {
+	if len(value) >= 2 && (value[0] == '[' || value[0] == '{') && value[len(value)-1] == closingBracket(value[0]) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// setOption resolves the value of an option and sets it on the parsed struct, returning whether
+// or not it was set. If previous is valid (i.e. the values of a slice option given before) the
+// resolved values are appended to it. Resolver errors are only returned for required options.
+func (t *StructTagCache[T]) setOption(ftv reflect.Value, field reflect.StructField, st StructTagOption, valueStr string, previous reflect.Value) (bool, error) {
+	if t.options.trimSpace && !(t.options.strictNumbers && st.numeric) {
+		valueStr = strings.TrimSpace(valueStr)
+	}
+	if t.options.urlDecode {
+		decoded, err := url.PathUnescape(valueStr)
+		if err != nil {
+			return false, t.optionError(ftv, field, st, valueStr, err)
+		}
+		valueStr = decoded
+		if isListResolver(st.Resolver) {
+			// decoded lists are parsed the same as lists that were not encoded
+			valueStr = trimBrackets(decoded)
+		}
+	}
+	if ref := strings.TrimPrefix(valueStr, SecretPrefix); t.options.secretProvider != nil && ref != valueStr {
+		secret, err := t.options.secretProvider(ref)
+		if err != nil {
+			return false, fmt.Errorf("unable to resolve secret '%s' for option '%s' for struct field %s: %w", ref, st.Name, field.Name, err)
+		}
+		valueStr = secret
+	}
+	if st.scalar {
+		if err := setScalar(ftv.FieldByIndex(st.fieldIndex()), st.Name, valueStr); err != nil {
+			return t.recoverOption(ftv, field, st, valueStr, err)
+		}
+		return true, nil
+	}
+	if multi, ok := st.Resolver.(MultiFieldUnmarshaler); ok {
+		var values map[int]reflect.Value
+		err := t.callResolver(field, st, func() (err error) {
+			values, err = multi.UnmarshalTagOptions(field, valueStr)
+			return err
+		})
+		if errors.Is(err, ErrResolverTimeout) || errors.Is(err, ErrResolverPanic) {
+			return false, err
+		} else if err != nil {
+			return false, t.optionError(ftv, field, st, valueStr, err)
+		}
+		for index, v := range values {
+			if index < 0 || index >= ftv.NumField() {
+				return false, fmt.Errorf("invalid field index %d for option '%s' for field '%s'", index, st.Name, field.Name)
+			}
+			if err := setValue(ftv, []int{index}, v, field); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	var v reflect.Value
+	err := t.callResolver(field, st, func() (err error) {
+		v, err = st.Resolver.UnmarshalTagOption(field, valueStr)
+		if err == nil && st.transform != nil {
+			v, err = st.transform(v)
+		}
+		return err
+	})
+	if errors.Is(err, ErrResolverTimeout) || errors.Is(err, ErrResolverPanic) {
+		return false, err
+	}
+	if err != nil {
+		// may potentially want to allow for a not-found error to be checked or something?
+		return t.recoverOption(ftv, field, st, valueStr, err)
+	}
+	if t.options.conversionHook != nil {
+		if v, err = t.options.conversionHook(field, v); err != nil {
+			return false, t.optionError(ftv, field, st, valueStr, err)
+		}
+	}
+	if previous.IsValid() && v.Kind() == reflect.Slice && v.CanConvert(previous.Type()) {
+		v = reflect.AppendSlice(previous, v.Convert(previous.Type()))
+	}
+	if err := st.validateValue(v); err != nil {
+		return false, fmt.Errorf("%w for struct field: %s", err, field.Name)
+	}
+	return true, setValue(ftv, st.fieldIndex(), v, field)
+}
+
+// setValue converts a resolved value to the type of a field in the parsed struct and sets it.
+func setValue(ftv reflect.Value, index []int, v reflect.Value, field reflect.StructField) error {
+	fv := ftv.FieldByIndex(index)
+	if !v.CanConvert(fv.Type()) {
+		return &ConversionError{
+			FieldName:  field.Name,
+			Option:     ftv.Type().FieldByIndex(index).Name,
+			TargetType: fv.Type(),
+			Err:        fmt.Errorf("unable to convert value of '%s' to type '%s' for field '%s'", ftv.Type().FieldByIndex(index).Name, fv.Type(), field.Name),
+		}
+	}
+	fv.Set(v.Convert(fv.Type()))
+	return nil
+}
+
+// Lookup returns the options defined for a struct tag option name. Unless the cache was created
+// WithAllowDuplicateNames there will be at most one option per name.
+func (t *StructTagCache[T]) Lookup(name string) ([]StructTagOption, bool) {
+	name = t.optionName(name)
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	opts, ok := t.structTagMap[name]
+	return opts, ok
+}
+
+// optionName returns the name that an option is stored under, which is lowercased if the cache was
+// created WithCaseInsensitiveKeys.
+func (t *StructTagCache[T]) optionName(name string) string {
+	if t.options.caseInsensitiveKeys {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
 // Get returns a []FieldTag for a type if it is found in the cache.
 func (t *StructTagCache[T]) Get(rType reflect.Type) ([]FieldTag[T], bool) {
 	rType = t.actualType(rType)
+	t.lock.RLock()
+	defer t.lock.RUnlock()
 	tags, ok := t.typeToTags[rType]
 	return tags, ok
 }
 
+// GetByName returns a []FieldTag for a type if it is found in the cache by its package path
+// qualified name (i.e. github.com/user/pkg.Type). This is useful when only the name of a type is
+// known (i.e. from serialized data). Types declared inside of functions can share the same name,
+// so nothing is found for a name that belongs to more than one cached type.
+func (t *StructTagCache[T]) GetByName(fullName string) ([]FieldTag[T], bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	types := t.nameToTypes[fullName]
+	if len(types) != 1 {
+		return nil, false
+	}
+	return t.typeToTags[types[0]], true
+}
+
 // GetOrAdd returns a []FieldTag for a type if it is found in the cache and adds/returns it
 // otherwise.
 func (t *StructTagCache[T]) GetOrAdd(rType reflect.Type) ([]FieldTag[T], error) {
+	if tags, ok := t.Get(rType); ok {
+		return tags, nil
+	}
 	rType = t.actualType(rType)
-	tags, ok := t.typeToTags[rType]
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	// another goroutine may have added the type while waiting for the lock
+	if tags, ok := t.typeToTags[rType]; ok {
+		return tags, nil
+	}
+	parsed, err := t.parseNested(rType)
+	if err != nil {
+		return nil, err
+	}
+	t.storeAll(parsed)
+	return parsed[rType], nil
+}
+
+// GetOrAddValue is the same as GetOrAdd for the type of the value given (i.e. a struct or a pointer
+// to one).
+func (t *StructTagCache[T]) GetOrAddValue(v any) ([]FieldTag[T], error) {
+	if v == nil {
+		return nil, errors.New("FieldTagCache cannot cache the type of a nil value")
+	}
+	return t.GetOrAdd(reflect.TypeOf(v))
+}
+
+// Clear removes every type from the cache while keeping the parsed definition of T, so that the
+// cache can be reused without validating the definition again.
+func (t *StructTagCache[T]) Clear() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.typeToTags = make(map[reflect.Type][]FieldTag[T])
+	t.nameToTypes = make(map[string][]reflect.Type)
+}
+
+// Len returns the number of types in the cache.
+func (t *StructTagCache[T]) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return len(t.typeToTags)
+}
+
+// Types returns a copy of every type in the cache in no particular order.
+func (t *StructTagCache[T]) Types() []reflect.Type {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	types := make([]reflect.Type, 0, len(t.typeToTags))
+	for rType := range t.typeToTags {
+		types = append(types, rType)
+	}
+	return types
+}
+
+// Rekey moves the parsed struct tags of a type to another type (i.e. a regenerated type with the
+// same shape) and returns whether or not they were moved. The new type is not validated.
+func (t *StructTagCache[T]) Rekey(oldType, newType reflect.Type) bool {
+	oldType = t.actualType(oldType)
+	newType = t.actualType(newType)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	tags, ok := t.typeToTags[oldType]
+	if !ok {
+		return false
+	}
+	t.remove(oldType)
+	t.store(newType, tags)
+	return true
+}
+
+// GetRaw returns the unparsed values (with any quotes or brackets removed) of the options scanned
+// from the struct tags of a type if it is found in the cache, mapped by field name and then by the
+// name of the option they were given to. Values given without a key that do not match an option
+// are mapped to themselves. The values of keys repeated for a slice option are joined by the
+// separator (i.e. `with=a,with=b` is `a,b`) while the last value is used for any other option.
+func (t *StructTagCache[T]) GetRaw(rType reflect.Type) (map[string]map[string]string, bool) {
+	tags, ok := t.Get(rType)
 	if !ok {
-		err := t.Add(rType)
-		return t.typeToTags[rType], err
+		return nil, false
+	}
+	raw := make(map[string]map[string]string, len(tags))
+	for _, tag := range tags {
+		values := make(map[string]string, len(tag.scanned))
+		for _, token := range tag.scanned {
+			values[token.Key] = token.Value
+		}
+		raw[tag.FieldName] = values
+	}
+	return raw, true
+}
+
+// lastScanned returns the value most recently scanned for an option.
+func lastScanned(scanned []TagToken, key string) string {
+	for i := len(scanned) - 1; i >= 0; i-- {
+		if scanned[i].Key == key {
+			return scanned[i].Value
+		}
+	}
+	return EmptyTag
+}
+
+// GroupOption is the name of the option used by GetGrouped to group fields (i.e. group=advanced).
+const GroupOption = "group"
+
+// GetGrouped returns the []FieldTag for a type (adding it to the cache if needed) grouped by the
+// value of their "group" option. Fields without a group (or every field if the definition struct
+// has no "group" option) are grouped under an empty string.
+func (t *StructTagCache[T]) GetGrouped(rType reflect.Type) (map[string][]FieldTag[T], error) {
+	tags, err := t.GetOrAdd(rType)
+	if err != nil {
+		return nil, err
+	}
+	opts, hasGroup := t.Lookup(GroupOption)
+	groups := make(map[string][]FieldTag[T])
+	for _, tag := range tags {
+		group := EmptyTag
+		if hasGroup {
+			v := reflect.Indirect(reflect.ValueOf(tag.Value))
+			if v.IsValid() {
+				group = fmt.Sprint(v.FieldByIndex(opts[0].fieldIndex()).Interface())
+			}
+		}
+		groups[group] = append(groups[group], tag)
+	}
+	return groups, nil
+}
+
+// GetField returns the FieldTag of a single field of a type by name (adding the type to the cache
+// if needed) and whether or not the field was found. Fields that failed to parse return false.
+func (t *StructTagCache[T]) GetField(rType reflect.Type, fieldName string) (FieldTag[T], bool) {
+	tags, err := t.GetOrAdd(rType)
+	if err != nil {
+		return FieldTag[T]{}, false
+	}
+	for _, tag := range tags {
+		if tag.FieldName == fieldName {
+			return tag, true
+		}
+	}
+	return FieldTag[T]{}, false
+}
+
+// GetByTagName returns the FieldTag of a single field of a type by the value of its $name option
+// (adding the type to the cache if needed) and whether or not the field was found. This is named
+// so it does not collide with GetByName, which looks up types by name. It always returns false if
+// T has no $name option.
+func (t *StructTagCache[T]) GetByTagName(rType reflect.Type, name string) (FieldTag[T], bool) {
+	tags, err := t.GetOrAdd(rType)
+	if err != nil || !t.hasName {
+		return FieldTag[T]{}, false
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	index := t.structTagMap[NameTag][0].fieldIndex()
+	for _, tag := range tags {
+		v := reflect.Indirect(reflect.ValueOf(tag.Value))
+		if v.IsValid() && fmt.Sprint(v.FieldByIndex(index).Interface()) == name {
+			return tag, true
+		}
+	}
+	return FieldTag[T]{}, false
+}
+
+// OptionOrder returns the names of the options scanned from the struct tag of a field in the order
+// they were given (adding the type to the cache if needed). This allows tags to be written back out
+// in the same order that they were read.
+func (t *StructTagCache[T]) OptionOrder(rType reflect.Type, fieldName string) ([]string, error) {
+	tags, err := t.GetOrAdd(rType)
+	if err != nil {
+		return nil, err
 	}
-	return tags, nil
+	for _, tag := range tags {
+		if tag.FieldName == fieldName {
+			order := make([]string, len(tag.scanned))
+			for i, token := range tag.scanned {
+				order[i] = token.Key
+			}
+			return order, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown struct field: %s", fieldName)
 }
 
 // ParseTagsForType[T any] parses the struct tags for a given type and converts them to type T.
@@ -378,3 +1782,20 @@ func ParseTagsForType[T any](tagName string, rType reflect.Type) ([]FieldTag[T],
 	}
 	return cache.GetOrAdd(rType)
 }
+
+// ParseTagsForValue[T any] parses the struct tags for the type of a value (i.e. a struct or a
+// pointer to one) and converts them to type T.
+func ParseTagsForValue[T any](tagName string, v any) ([]FieldTag[T], error) {
+	cache, err := NewFieldTagCache[T](tagName)
+	if err != nil {
+		return nil, err
+	}
+	return cache.GetOrAddValue(v)
+}
+
+// TagsFor[V any] parses the struct tags for the type of a sample value (i.e. the zero value of a
+// type parameter) and converts them to type V. This allows generic code to get the tags of its
+// type parameters without naming their types. It is the same as ParseTagsForValue.
+func TagsFor[V any](tagName string, sample any) ([]FieldTag[V], error) {
+	return ParseTagsForValue[V](tagName, sample)
+}
@@ -1,7 +1,13 @@
 package spectagular_test
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -48,6 +54,26 @@ func TestNewTagCache(t *testing.T) {
 	if cache == nil || err != nil {
 		t.Error("TestNewTagCache: failed struct validation", err.Error())
 	}
+	type NewTestStruct struct {
+		F int `test:"name=value"`
+	}
+	pointerCache, err := spectagular.NewFieldTagCache[*NewTest]("test")
+	if pointerCache == nil || err != nil {
+		t.Fatal("TestNewTagCache: failed pointer struct validation", err)
+	}
+	tags, err := pointerCache.GetOrAdd(reflect.TypeOf(NewTestStruct{}))
+	if err != nil {
+		t.Fatal("TestNewTagCache: failed pointer struct tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.S, "value", "TestNewTagCache: wrong parsed pointer value:")
+	valueTags, err := cache.GetOrAdd(reflect.TypeOf(NewTestStruct{}))
+	if err != nil {
+		t.Fatal("TestNewTagCache: failed struct tags validation", err.Error())
+	}
+	assertEqual(t, valueTags[0].Value.S, tags[0].Value.S, "TestNewTagCache: wrong parsed value:")
+	if _, err := spectagular.NewFieldTagCache[*string]("test"); err == nil {
+		t.Error("TestNewTagCache: failed pointer to non struct validation")
+	}
 }
 
 func TestNewTagCacheInvalid(t *testing.T) {
@@ -63,6 +89,13 @@ func TestNewTagCacheInvalid(t *testing.T) {
 	if badCache != nil || err == nil {
 		t.Error("TestNewTagCacheInvalid: failed duplicate name test")
 	}
+	type Empty struct {
+		S string `structtag:"name"`
+	}
+	emptyCache, err := spectagular.NewFieldTagCache[Empty]("")
+	if emptyCache != nil || err == nil {
+		t.Error("TestNewTagCacheInvalid: failed empty tag name test")
+	}
 }
 
 func TestQuotedTags(t *testing.T) {
@@ -246,3 +279,2853 @@ func TestTypeConversion(t *testing.T) {
 		t.Error("TestTypeConversion: failed invalid array validation")
 	}
 }
+
+func TestQuotedSliceTags(t *testing.T) {
+	type TestQuotedSliceTag struct {
+		StringList []string `structtag:"sa"`
+		String     string   `structtag:"s"`
+	}
+	type TestQuotedSliceStruct struct {
+		Bracket int `test:"sa=['a]b',c],s=after"`
+		Escaped int `test:"sa=['a\\'],b',c\\]]"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestQuotedSliceTag]("test")
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestQuotedSliceStruct{}))
+	if err != nil {
+		t.Fatal("TestQuotedSliceTags: failed quoted slice tags validation", err.Error())
+	}
+	assertEqual(t, len(tags[0].Value.StringList), 2, "TestQuotedSliceTags: wrong parsed slice length:")
+	assertEqual(t, tags[0].Value.StringList[0], "a]b", "TestQuotedSliceTags: wrong parsed slice value:")
+	assertEqual(t, tags[0].Value.StringList[1], "c", "TestQuotedSliceTags: wrong parsed slice value:")
+	assertEqual(t, tags[0].Value.String, "after", "TestQuotedSliceTags: wrong parsed value:")
+	assertEqual(t, len(tags[1].Value.StringList), 2, "TestQuotedSliceTags: wrong parsed slice length:")
+	assertEqual(t, tags[1].Value.StringList[0], "a'],b", "TestQuotedSliceTags: wrong parsed slice value:")
+	assertEqual(t, tags[1].Value.StringList[1], "c]", "TestQuotedSliceTags: wrong parsed slice value:")
+}
+
+func TestTransformTags(t *testing.T) {
+	type TestTransformTag struct {
+		Name  string `structtag:"$name,transform=upper"`
+		Other string `structtag:"o"`
+	}
+	type TestTransformStruct struct {
+		Field int `test:"name,o=other"`
+	}
+	upper := spectagular.WithTransform("upper", func(v reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(strings.ToUpper(v.String())), nil
+	})
+	cache, err := spectagular.NewFieldTagCacheWithOptions[TestTransformTag]("test", upper)
+	if err != nil {
+		t.Fatal("TestTransformTags: failed transform validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestTransformStruct{}))
+	if err != nil {
+		t.Fatal("TestTransformTags: failed transform tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "NAME", "TestTransformTags: wrong transformed value:")
+	assertEqual(t, tags[0].Value.Other, "other", "TestTransformTags: wrong parsed value:")
+	badCache, err := spectagular.NewFieldTagCache[TestTransformTag]("test")
+	if badCache != nil || err == nil {
+		t.Error("TestTransformTags: failed unknown transform validation")
+	}
+}
+
+type colonTokenizer struct{}
+
+func (c colonTokenizer) Tokenize(tag string) ([]spectagular.TagToken, error) {
+	keys := []string{"", "type", ""}
+	tokens := make([]spectagular.TagToken, 0)
+	for i, value := range strings.Split(tag, ":") {
+		if i >= len(keys) {
+			break
+		}
+		tokens = append(tokens, spectagular.TagToken{Key: keys[i], Value: value})
+	}
+	return tokens, nil
+}
+
+func TestTokenizerTags(t *testing.T) {
+	type TestTokenizerTag struct {
+		Name string `structtag:"$name"`
+		Type string `structtag:"type"`
+		Flag bool   `structtag:"flag"`
+	}
+	type TestTokenizerStruct struct {
+		Full int `test:"id:int:flag"`
+		Part int `test:"count:uint"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestTokenizerTag]("test", spectagular.WithTokenizer(colonTokenizer{}))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestTokenizerStruct{}))
+	if err != nil {
+		t.Fatal("TestTokenizerTags: failed tokenizer tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "id", "TestTokenizerTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Type, "int", "TestTokenizerTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Flag, true, "TestTokenizerTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Name, "count", "TestTokenizerTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Type, "uint", "TestTokenizerTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Flag, false, "TestTokenizerTags: wrong parsed value:")
+}
+
+type TestProviderTag struct {
+	Short string `structtag:"s"`
+	Extra string
+	Other string `structtag:"o"`
+}
+
+func (p TestProviderTag) TagSchema() []spectagular.StructTagOption {
+	return []spectagular.StructTagOption{
+		{Name: "short", Required: true, FieldIndex: 0},
+		{Name: "extra", FieldIndex: 1},
+	}
+}
+
+func TestTagSchemaProvider(t *testing.T) {
+	type TestProviderStruct struct {
+		Field int `test:"short=a,extra=b,o=c,s=d"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestProviderTag]("test")
+	if err != nil {
+		t.Fatal("TestTagSchemaProvider: failed provider validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestProviderStruct{}))
+	if err != nil {
+		t.Fatal("TestTagSchemaProvider: failed provider tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Short, "a", "TestTagSchemaProvider: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Extra, "b", "TestTagSchemaProvider: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Other, "c", "TestTagSchemaProvider: wrong parsed value:")
+	type TestProviderMissing struct {
+		Field int `test:"s=d"`
+	}
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestProviderMissing{}))
+	if err == nil {
+		t.Error("TestTagSchemaProvider: failed provided required validation")
+	}
+}
+
+func TestValidateType(t *testing.T) {
+	type TestValidateTag struct {
+		Required string `structtag:"r,required"`
+	}
+	type TestValidateStruct struct {
+		Field int `test:"r=value"`
+	}
+	type TestValidateInvalid struct {
+		Field int `test:"other=value"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestValidateTag]("test")
+	if err := cache.ValidateType(reflect.TypeOf(TestValidateStruct{})); err != nil {
+		t.Error("TestValidateType: failed valid type validation", err.Error())
+	}
+	if _, ok := cache.Get(reflect.TypeOf(TestValidateStruct{})); ok {
+		t.Error("TestValidateType: validated type was cached")
+	}
+	if err := cache.ValidateType(reflect.TypeOf(TestValidateInvalid{})); err == nil {
+		t.Error("TestValidateType: failed invalid type validation")
+	}
+}
+
+func TestDescriptionTag(t *testing.T) {
+	type TestDescriptionTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestDescriptionStruct struct {
+		Described int `test:"described" desc:"a described field"`
+		Plain     int `test:"plain"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestDescriptionTag]("test", spectagular.WithDescriptionTag("desc"))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestDescriptionStruct{}))
+	if err != nil {
+		t.Fatal("TestDescriptionTag: failed description tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Description, "a described field", "TestDescriptionTag: wrong description:")
+	assertEqual(t, tags[1].Description, "", "TestDescriptionTag: wrong description:")
+}
+
+func TestPositionalTags(t *testing.T) {
+	type TestPositionalTag struct {
+		Type     string `structtag:"type,positional=0"`
+		Format   string `structtag:"format,positional=1"`
+		Nullable bool   `structtag:"nullable"`
+	}
+	type TestPositionalStruct struct {
+		Both    int `test:"int,base10,nullable"`
+		First   int `test:"string"`
+		Keyed   int `test:"format=hex,type=uint"`
+		Skipped int `test:"nullable,float"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestPositionalTag]("test")
+	if err != nil {
+		t.Fatal("TestPositionalTags: failed positional validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestPositionalStruct{}))
+	if err != nil {
+		t.Fatal("TestPositionalTags: failed positional tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Type, "int", "TestPositionalTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Format, "base10", "TestPositionalTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Nullable, true, "TestPositionalTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Type, "string", "TestPositionalTags: wrong parsed value:")
+	assertEqual(t, tags[2].Value.Type, "uint", "TestPositionalTags: wrong parsed value:")
+	assertEqual(t, tags[2].Value.Format, "hex", "TestPositionalTags: wrong parsed value:")
+	// positional values are claimed before keys are matched
+	assertEqual(t, tags[3].Value.Type, "nullable", "TestPositionalTags: wrong parsed value:")
+	assertEqual(t, tags[3].Value.Nullable, false, "TestPositionalTags: wrong parsed value:")
+	assertEqual(t, tags[3].Value.Format, "float", "TestPositionalTags: wrong parsed value:")
+	type TestPositionalConflict struct {
+		Name string `structtag:"$name"`
+		Type string `structtag:"type,positional=0"`
+	}
+	badCache, err := spectagular.NewFieldTagCache[TestPositionalConflict]("test")
+	if badCache != nil || err == nil {
+		t.Error("TestPositionalTags: failed conflicting position validation")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	type TestFingerprintTag struct {
+		Name    string         `structtag:"$name"`
+		List    []string       `structtag:"list"`
+		Weights map[string]int `structtag:"weights"`
+	}
+	type TestFingerprintStruct struct {
+		Field int `test:"field,list=[a,b],weights='a:1;b:2;c:3'"`
+	}
+	type TestFingerprintSame struct {
+		Field int `test:"field,list=[a,b],weights='c:3;b:2;a:1'"`
+	}
+	type TestFingerprintChanged struct {
+		Field int `test:"field,list=[a,c],weights='a:1;b:2;c:3'"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestFingerprintTag]("test")
+	first, err := cache.Fingerprint(reflect.TypeOf(TestFingerprintStruct{}))
+	if err != nil {
+		t.Fatal("TestFingerprint: failed fingerprint", err.Error())
+	}
+	second, _ := cache.Fingerprint(reflect.TypeOf(TestFingerprintStruct{}))
+	assertEqual(t, first, second, "TestFingerprint: unstable fingerprint:")
+	same, _ := cache.Fingerprint(reflect.TypeOf(TestFingerprintSame{}))
+	assertEqual(t, first, same, "TestFingerprint: fingerprint depends on map order:")
+	changed, _ := cache.Fingerprint(reflect.TypeOf(TestFingerprintChanged{}))
+	assertNotEqual(t, first, changed, "TestFingerprint: fingerprint did not change:")
+	type TestFingerprintEmbedded struct {
+		Field int `test:"field"`
+	}
+	type TestFingerprintFirst struct {
+		TestFingerprintEmbedded
+	}
+	type TestFingerprintSecond struct {
+		_ int
+		TestFingerprintEmbedded
+	}
+	embeddedFirst, _ := cache.Fingerprint(reflect.TypeOf(TestFingerprintFirst{}))
+	embeddedSecond, _ := cache.Fingerprint(reflect.TypeOf(TestFingerprintSecond{}))
+	assertNotEqual(t, embeddedFirst, embeddedSecond, "TestFingerprint: fingerprint ignored the field path:")
+}
+
+func TestAllowDuplicateNames(t *testing.T) {
+	type TestDuplicateTag struct {
+		Raw    string `structtag:"size"`
+		Parsed int    `structtag:"size"`
+	}
+	type TestDuplicateStruct struct {
+		Field int `test:"size=10"`
+	}
+	cache, err := spectagular.NewFieldTagCacheWithOptions[TestDuplicateTag]("test", spectagular.WithAllowDuplicateNames())
+	if err != nil {
+		t.Fatal("TestAllowDuplicateNames: failed duplicate name validation", err.Error())
+	}
+	opts, ok := cache.Lookup("size")
+	if !ok {
+		t.Fatal("TestAllowDuplicateNames: failed duplicate name lookup")
+	}
+	assertEqual(t, len(opts), 2, "TestAllowDuplicateNames: wrong number of options:")
+	assertEqual(t, opts[0].FieldIndex, 0, "TestAllowDuplicateNames: wrong option field index:")
+	assertEqual(t, opts[1].FieldIndex, 1, "TestAllowDuplicateNames: wrong option field index:")
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestDuplicateStruct{}))
+	if err != nil {
+		t.Fatal("TestAllowDuplicateNames: failed duplicate name tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Raw, "10", "TestAllowDuplicateNames: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Parsed, 10, "TestAllowDuplicateNames: wrong parsed value:")
+}
+
+func TestRekey(t *testing.T) {
+	type TestRekeyTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestRekeyOld struct {
+		Field int `test:"field"`
+	}
+	type TestRekeyNew struct {
+		Field int
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestRekeyTag]("test")
+	if cache.Rekey(reflect.TypeOf(TestRekeyOld{}), reflect.TypeOf(TestRekeyNew{})) {
+		t.Error("TestRekey: rekeyed a type that was not cached")
+	}
+	cache.Add(reflect.TypeOf(TestRekeyOld{}))
+	if !cache.Rekey(reflect.TypeOf(&TestRekeyOld{}), reflect.TypeOf(TestRekeyNew{})) {
+		t.Fatal("TestRekey: failed to rekey a cached type")
+	}
+	if _, ok := cache.Get(reflect.TypeOf(TestRekeyOld{})); ok {
+		t.Error("TestRekey: old type is still cached")
+	}
+	tags, ok := cache.Get(reflect.TypeOf(TestRekeyNew{}))
+	if !ok {
+		t.Fatal("TestRekey: new type is not cached")
+	}
+	assertEqual(t, tags[0].Value.Name, "field", "TestRekey: wrong rekeyed value:")
+}
+
+func TestSliceLengths(t *testing.T) {
+	type TestLengthTag struct {
+		Tags []string `structtag:"tags,minlen=1,maxlen=3"`
+	}
+	type TestLengthWithin struct {
+		One   int `test:"tags=[a]"`
+		Three int `test:"tags=[a,b,c]"`
+	}
+	type TestLengthUnder struct {
+		Empty int `test:"tags=[]"`
+	}
+	type TestLengthOver struct {
+		Four int `test:"tags=[a,b,c,d]"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestLengthTag]("test")
+	if err != nil {
+		t.Fatal("TestSliceLengths: failed length validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestLengthWithin{}))
+	if err != nil {
+		t.Fatal("TestSliceLengths: failed length tags validation", err.Error())
+	}
+	assertEqual(t, len(tags[0].Value.Tags), 1, "TestSliceLengths: wrong parsed slice length:")
+	assertEqual(t, len(tags[1].Value.Tags), 3, "TestSliceLengths: wrong parsed slice length:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestLengthUnder{}))
+	if err == nil || !strings.Contains(err.Error(), "minlen 1") || !strings.Contains(err.Error(), "Empty") {
+		t.Error("TestSliceLengths: failed minlen validation", err)
+	}
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestLengthOver{}))
+	if err == nil || !strings.Contains(err.Error(), "maxlen 3") || !strings.Contains(err.Error(), "Four") {
+		t.Error("TestSliceLengths: failed maxlen validation", err)
+	}
+	type TestLengthInvalid struct {
+		Tag string `structtag:"tag,minlen=1"`
+	}
+	badCache, err := spectagular.NewFieldTagCache[TestLengthInvalid]("test")
+	if badCache != nil || err == nil {
+		t.Error("TestSliceLengths: failed non slice length validation")
+	}
+}
+
+func TestNumericBounds(t *testing.T) {
+	type TestBoundsTag struct {
+		Workers int      `structtag:"workers" min:"1" max:"64"`
+		Ratio   *float64 `structtag:"ratio" min:"0" max:"0.5"`
+	}
+	type TestBoundsWithin struct {
+		Low  int `test:"workers=1,ratio=0"`
+		High int `test:"workers=64,ratio=0.5"`
+	}
+	type TestBoundsUnder struct {
+		Zero int `test:"workers=0"`
+	}
+	type TestBoundsOver struct {
+		Half int `test:"workers=2,ratio=0.75"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestBoundsTag]("test")
+	if err != nil {
+		t.Fatal("TestNumericBounds: failed bounds validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestBoundsWithin{}))
+	if err != nil {
+		t.Fatal("TestNumericBounds: failed bounds tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Workers, 1, "TestNumericBounds: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Workers, 64, "TestNumericBounds: wrong parsed value:")
+	assertEqual(t, *tags[1].Value.Ratio, 0.5, "TestNumericBounds: wrong parsed value:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestBoundsUnder{}))
+	if err == nil || !strings.Contains(err.Error(), "min 1") || !strings.Contains(err.Error(), "Zero") {
+		t.Error("TestNumericBounds: failed min validation", err)
+	}
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestBoundsOver{}))
+	if err == nil || !strings.Contains(err.Error(), "max 0.5") || !strings.Contains(err.Error(), "Half") {
+		t.Error("TestNumericBounds: failed max validation", err)
+	}
+	type TestBoundsInvalidType struct {
+		Name string `structtag:"name" min:"1"`
+	}
+	if _, err := spectagular.NewFieldTagCache[TestBoundsInvalidType]("test"); err == nil {
+		t.Error("TestNumericBounds: failed non numeric bounds validation")
+	}
+	type TestBoundsInvalidValue struct {
+		Workers int `structtag:"workers" max:"many"`
+	}
+	if _, err := spectagular.NewFieldTagCache[TestBoundsInvalidValue]("test"); err == nil || !strings.Contains(err.Error(), "invalid max") {
+		t.Error("TestNumericBounds: failed invalid bound validation", err)
+	}
+	type TestBoundsDurationTag struct {
+		Timeout time.Duration `structtag:"timeout" max:"1000000000"`
+	}
+	type TestBoundsDuration struct {
+		Within int `test:"timeout=1s"`
+		Over   int `test:"timeout=2s"`
+	}
+	durationCache, _ := spectagular.NewFieldTagCache[TestBoundsDurationTag]("test")
+	if _, err := durationCache.GetOrAdd(reflect.TypeOf(TestBoundsDuration{})); err == nil || !strings.Contains(err.Error(), "Over") {
+		t.Error("TestNumericBounds: failed duration bound validation", err)
+	}
+}
+
+func TestAllowedValues(t *testing.T) {
+	type TestAllowedTag struct {
+		Mode  string `structtag:"mode" enum:"read,write,append"`
+		Level *uint8 `structtag:"level" enum:"1,2,3"`
+	}
+	type TestAllowedValid struct {
+		Read   int `test:"mode=read,level=1"`
+		Append int `test:"mode=append,level=03"`
+		Absent int `test:""`
+	}
+	type TestAllowedInvalidString struct {
+		Delete int `test:"mode=delete"`
+	}
+	type TestAllowedInvalidInt struct {
+		Four int `test:"mode=write,level=4"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestAllowedTag]("test")
+	if err != nil {
+		t.Fatal("TestAllowedValues: failed enum validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestAllowedValid{}))
+	if err != nil {
+		t.Fatal("TestAllowedValues: failed enum tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Mode, "read", "TestAllowedValues: wrong parsed value:")
+	assertEqual(t, int(*tags[0].Value.Level), 1, "TestAllowedValues: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Mode, "append", "TestAllowedValues: wrong parsed value:")
+	assertEqual(t, int(*tags[1].Value.Level), 3, "TestAllowedValues: wrong parsed value:")
+	assertEqual(t, tags[2].Value.Mode, "", "TestAllowedValues: wrong absent value:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestAllowedInvalidString{}))
+	if err == nil || !strings.Contains(err.Error(), "'delete'") || !strings.Contains(err.Error(), "Delete") {
+		t.Error("TestAllowedValues: failed invalid string validation", err)
+	}
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestAllowedInvalidInt{}))
+	if err == nil || !strings.Contains(err.Error(), "'4'") || !strings.Contains(err.Error(), "Four") {
+		t.Error("TestAllowedValues: failed invalid integer validation", err)
+	}
+	type TestAllowedInvalidType struct {
+		Ratio float64 `structtag:"ratio" enum:"0.5,1"`
+	}
+	if _, err := spectagular.NewFieldTagCache[TestAllowedInvalidType]("test"); err == nil {
+		t.Error("TestAllowedValues: failed non string or integer enum validation")
+	}
+	type TestAllowedInvalidValue struct {
+		Level int `structtag:"level" enum:"1,two"`
+	}
+	if _, err := spectagular.NewFieldTagCache[TestAllowedInvalidValue]("test"); err == nil || !strings.Contains(err.Error(), "'two'") {
+		t.Error("TestAllowedValues: failed invalid enum value validation", err)
+	}
+}
+
+type TestTypeNameStruct struct {
+	Field int `test:"field"`
+}
+
+func TestGetByName(t *testing.T) {
+	type TestTypeNameTag struct {
+		Name string `structtag:"$name"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestTypeNameTag]("test")
+	cache.Add(reflect.TypeOf(&TestTypeNameStruct{}))
+	tags, ok := cache.GetByName("github.com/matt1484/spectagular_test.TestTypeNameStruct")
+	if !ok {
+		t.Fatal("TestGetByName: failed lookup by full type name")
+	}
+	assertEqual(t, tags[0].Value.Name, "field", "TestGetByName: wrong parsed value:")
+	if _, ok := cache.GetByName("TestTypeNameStruct"); ok {
+		t.Error("TestGetByName: found type by short name")
+	}
+}
+
+func firstLocalType() reflect.Type {
+	type Local struct {
+		Field int `test:"first"`
+	}
+	return reflect.TypeOf(Local{})
+}
+
+func secondLocalType() reflect.Type {
+	type Local struct {
+		Field int `test:"second"`
+	}
+	return reflect.TypeOf(Local{})
+}
+
+func TestGetByNameLocalTypes(t *testing.T) {
+	type TestTypeNameTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestRekeyStruct struct {
+		Field int `test:"rekeyed"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestTypeNameTag]("test")
+	first, second := firstLocalType(), secondLocalType()
+	cache.Add(first)
+	tags, ok := cache.GetByName("github.com/matt1484/spectagular_test.Local")
+	if !ok {
+		t.Fatal("TestGetByNameLocalTypes: failed lookup by full type name")
+	}
+	assertEqual(t, tags[0].Value.Name, "first", "TestGetByNameLocalTypes: wrong parsed value:")
+	cache.Add(second)
+	if _, ok := cache.GetByName("github.com/matt1484/spectagular_test.Local"); ok {
+		t.Error("TestGetByNameLocalTypes: found ambiguous type name")
+	}
+	cache.Rekey(second, reflect.TypeOf(TestRekeyStruct{}))
+	tags, ok = cache.GetByName("github.com/matt1484/spectagular_test.Local")
+	if !ok {
+		t.Fatal("TestGetByNameLocalTypes: lost type name of a type that is still cached")
+	}
+	assertEqual(t, tags[0].Value.Name, "first", "TestGetByNameLocalTypes: wrong parsed value after rekey:")
+}
+
+func TestFieldType(t *testing.T) {
+	type TestFieldTypeTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestFieldTypeStruct struct {
+		Int     int      `test:"int"`
+		Strings []string `test:"strings"`
+		Pointer *float64 `test:"pointer"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestFieldTypeTag]("test", reflect.TypeOf(TestFieldTypeStruct{}))
+	if err != nil {
+		t.Fatal("TestFieldType: failed field type tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].FieldType.String(), "int", "TestFieldType: wrong field type:")
+	assertEqual(t, tags[1].FieldType.String(), "[]string", "TestFieldType: wrong field type:")
+	assertEqual(t, tags[2].FieldType.String(), "*float64", "TestFieldType: wrong field type:")
+	if tags[1].FieldType != reflect.TypeOf([]string{}) {
+		t.Error("TestFieldType: field type does not match declared type")
+	}
+}
+
+type TestSize struct{}
+
+func (s TestSize) UnmarshalTagOptions(field reflect.StructField, value string) (map[int]reflect.Value, error) {
+	width, height, ok := strings.Cut(value, "x")
+	if !ok {
+		return nil, errors.New("size must be of the form WxH")
+	}
+	w, err := strconv.Atoi(width)
+	if err != nil {
+		return nil, err
+	}
+	h, err := strconv.Atoi(height)
+	if err != nil {
+		return nil, err
+	}
+	return map[int]reflect.Value{0: reflect.ValueOf(w), 1: reflect.ValueOf(h)}, nil
+}
+
+func TestMultiFieldTags(t *testing.T) {
+	type TestMultiFieldTag struct {
+		Width  int      `structtag:"width"`
+		Height int      `structtag:"height"`
+		Size   TestSize `structtag:"size"`
+	}
+	type TestMultiFieldStruct struct {
+		Shorthand int `test:"size=10x20"`
+		Explicit  int `test:"width=1,height=2"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestMultiFieldTag]("test")
+	if err != nil {
+		t.Fatal("TestMultiFieldTags: failed multi field validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestMultiFieldStruct{}))
+	if err != nil {
+		t.Fatal("TestMultiFieldTags: failed multi field tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Width, 10, "TestMultiFieldTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Height, 20, "TestMultiFieldTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Width, 1, "TestMultiFieldTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Height, 2, "TestMultiFieldTags: wrong parsed value:")
+}
+
+func TestTrimSpace(t *testing.T) {
+	type TestTrimTag struct {
+		String string `structtag:"s"`
+		Int    int    `structtag:"i"`
+		Bool   bool   `structtag:"b"`
+	}
+	type TestTrimStruct struct {
+		Field int `test:"s= padded ,i= 5 , b"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestTrimTag]("test", spectagular.WithTrimSpace())
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestTrimStruct{}))
+	if err != nil {
+		t.Fatal("TestTrimSpace: failed trimmed tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.String, "padded", "TestTrimSpace: wrong trimmed value:")
+	assertEqual(t, tags[0].Value.Int, 5, "TestTrimSpace: wrong trimmed value:")
+	assertEqual(t, tags[0].Value.Bool, true, "TestTrimSpace: wrong trimmed value:")
+	strictCache, _ := spectagular.NewFieldTagCacheWithOptions[TestTrimTag]("test", spectagular.WithTrimSpace(), spectagular.WithStrictNumbers())
+	tags, err = strictCache.GetOrAdd(reflect.TypeOf(TestTrimStruct{}))
+	if err != nil {
+		t.Fatal("TestTrimSpace: failed strict tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.String, "padded", "TestTrimSpace: wrong trimmed value:")
+	assertEqual(t, tags[0].Value.Int, 0, "TestTrimSpace: accepted untrimmed number:")
+	type TestStrictRequiredTag struct {
+		Int int `structtag:"i,required"`
+	}
+	strictRequired, _ := spectagular.NewFieldTagCacheWithOptions[TestStrictRequiredTag]("test", spectagular.WithTrimSpace(), spectagular.WithStrictNumbers())
+	if _, err = strictRequired.GetOrAdd(reflect.TypeOf(TestTrimStruct{})); err == nil {
+		t.Error("TestTrimSpace: failed strict number validation")
+	}
+}
+
+func TestAllTags(t *testing.T) {
+	type TestAllTagsStruct struct {
+		Field string `json:"id,omitempty" db:"user_id" test:"name,opt='quoted \"value\"'"`
+	}
+	tags := spectagular.AllTags(reflect.TypeOf(TestAllTagsStruct{}).Field(0))
+	assertEqual(t, len(tags), 3, "TestAllTags: wrong number of namespaces:")
+	assertEqual(t, tags["json"], "id,omitempty", "TestAllTags: wrong json value:")
+	assertEqual(t, tags["db"], "user_id", "TestAllTags: wrong db value:")
+	assertEqual(t, tags["test"], `name,opt='quoted "value"'`, "TestAllTags: wrong test value:")
+}
+
+func TestNameMeta(t *testing.T) {
+	type TestNameMetaTag struct {
+		Name     string `structtag:"$name"`
+		Required bool   `structtag:"required"`
+	}
+	type TestNameMetaStruct struct {
+		Email string `test:"email|string,required"`
+		Plain string `test:"plain"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestNameMetaTag]("test", spectagular.WithNameMetaSeparator("|"))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestNameMetaStruct{}))
+	if err != nil {
+		t.Fatal("TestNameMeta: failed name meta tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "email", "TestNameMeta: wrong name:")
+	assertEqual(t, tags[0].NameMeta, "string", "TestNameMeta: wrong name meta:")
+	assertEqual(t, tags[0].Value.Required, true, "TestNameMeta: wrong required value:")
+	assertEqual(t, tags[1].Value.Name, "plain", "TestNameMeta: wrong name:")
+	assertEqual(t, tags[1].NameMeta, "", "TestNameMeta: wrong name meta:")
+	defaultCache, _ := spectagular.NewFieldTagCache[TestNameMetaTag]("test")
+	tags, err = defaultCache.GetOrAdd(reflect.TypeOf(TestNameMetaStruct{}))
+	if err != nil {
+		t.Fatal("TestNameMeta: failed default tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "email|string", "TestNameMeta: wrong default name:")
+}
+
+func TestTailTags(t *testing.T) {
+	type TestTailTag struct {
+		Command string `structtag:"command,positional=0"`
+		Rest    string `structtag:"$tail"`
+	}
+	type TestTailStruct struct {
+		Run    int `cmd:"run,--verbose,extra args here"`
+		Quoted int `cmd:"'run now',a=[b,c]"`
+		Only   int `cmd:"build"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestTailTag]("cmd")
+	if err != nil {
+		t.Fatal("TestTailTags: failed tail validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestTailStruct{}))
+	if err != nil {
+		t.Fatal("TestTailTags: failed tail tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Command, "run", "TestTailTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Rest, "--verbose,extra args here", "TestTailTags: wrong tail:")
+	assertEqual(t, tags[1].Value.Command, "run now", "TestTailTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Rest, "a=[b,c]", "TestTailTags: wrong tail:")
+	assertEqual(t, tags[2].Value.Command, "build", "TestTailTags: wrong parsed value:")
+	assertEqual(t, tags[2].Value.Rest, "", "TestTailTags: wrong tail:")
+	type TestTailInvalid struct {
+		Rest int `structtag:"$tail"`
+	}
+	if _, err = spectagular.NewFieldTagCache[TestTailInvalid]("cmd"); err == nil {
+		t.Error("TestTailTags: failed non string tail validation")
+	}
+}
+
+func TestValidateCache(t *testing.T) {
+	type TestValidateCacheTag struct {
+		Name  string `structtag:"$name"`
+		Limit int    `structtag:"limit"`
+	}
+	type TestWithLimit struct {
+		Field int `test:"field,limit=5"`
+	}
+	type TestWithoutLimit struct {
+		Field int `test:"field"`
+	}
+	type TestAlsoWithoutLimit struct {
+		Field int `test:"field"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestValidateCacheTag]("test")
+	for _, rType := range []reflect.Type{reflect.TypeOf(TestWithLimit{}), reflect.TypeOf(TestWithoutLimit{}), reflect.TypeOf(TestAlsoWithoutLimit{})} {
+		if err := cache.Add(rType); err != nil {
+			t.Fatal("TestValidateCache: failed tags validation", err.Error())
+		}
+	}
+	if err := cache.ValidateCache(); err != nil {
+		t.Fatal("TestValidateCache: failed cache validation", err.Error())
+	}
+	if err := cache.SetRequired("unknown", true); err == nil {
+		t.Error("TestValidateCache: failed unknown option validation")
+	}
+	if err := cache.SetRequired("limit", true); err != nil {
+		t.Fatal("TestValidateCache: failed to set required", err.Error())
+	}
+	err := cache.ValidateCache()
+	var errs spectagular.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatal("TestValidateCache: failed required cache validation", err)
+	}
+	assertEqual(t, len(errs), 2, "TestValidateCache: wrong number of invalid types:")
+	assertEqual(t, strings.Contains(errs[0].Error(), "TestAlsoWithoutLimit"), true, "TestValidateCache: wrong invalid type:")
+	assertEqual(t, strings.Contains(errs[1].Error(), "TestWithoutLimit"), true, "TestValidateCache: wrong invalid type:")
+	// Is and As are called directly since errors.Is and errors.As only use Unwrap() []error
+	// since Go 1.20
+	assertEqual(t, errs.Is(spectagular.ErrMissingRequired), true, "TestValidateCache: wrong Is result:")
+	assertEqual(t, errs.Is(spectagular.ErrUnsupportedType), false, "TestValidateCache: wrong Is result:")
+	var conversion *spectagular.ConversionError
+	assertEqual(t, errs.As(&conversion), false, "TestValidateCache: wrong As result:")
+	var wrapped interface{ Unwrap() error }
+	assertEqual(t, errs.As(&wrapped), true, "TestValidateCache: wrong As result:")
+	if err := cache.SetRequired("limit", false); err != nil {
+		t.Fatal("TestValidateCache: failed to unset required", err.Error())
+	}
+	if err := cache.ValidateCache(); err != nil {
+		t.Error("TestValidateCache: failed relaxed cache validation", err.Error())
+	}
+}
+
+type benchmarkScalarTag struct {
+	Name     string  `structtag:"$name"`
+	Required bool    `structtag:"required"`
+	Min      int     `structtag:"min"`
+	Max      uint16  `structtag:"max"`
+	Ratio    float32 `structtag:"ratio"`
+	Format   string  `structtag:"format"`
+}
+
+type benchmarkScalarStruct struct {
+	A int `test:"a,required,min=1,max=10,ratio=0.5,format=hex"`
+	B int `test:"b,min=-5,max=65535,ratio=1e3"`
+	C int `test:"c,required=false,format='quoted value'"`
+}
+
+func TestScalarTags(t *testing.T) {
+	tags, err := spectagular.ParseTagsForType[benchmarkScalarTag]("test", reflect.TypeOf(benchmarkScalarStruct{}))
+	if err != nil {
+		t.Fatal("TestScalarTags: failed scalar tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "a", "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Required, true, "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Min, 1, "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Max, 10, "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Ratio, 0.5, "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Format, "hex", "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Min, -5, "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Max, 65535, "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Ratio, 1000, "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[2].Value.Required, false, "TestScalarTags: wrong parsed value:")
+	assertEqual(t, tags[2].Value.Format, "quoted value", "TestScalarTags: wrong parsed value:")
+	type TestScalarOverflow struct {
+		Overflow int `test:"a,max=65536"`
+	}
+	tags, err = spectagular.ParseTagsForType[benchmarkScalarTag]("test", reflect.TypeOf(TestScalarOverflow{}))
+	if err != nil {
+		t.Fatal("TestScalarTags: failed overflow tags validation", err.Error())
+	}
+	// invalid values for options that are not required are ignored
+	assertEqual(t, tags[0].Value.Max, 0, "TestScalarTags: wrong parsed overflow:")
+}
+
+func BenchmarkScalarTags(b *testing.B) {
+	cache, err := spectagular.NewFieldTagCache[benchmarkScalarTag]("test")
+	if err != nil {
+		b.Fatal(err)
+	}
+	rType := reflect.TypeOf(benchmarkScalarStruct{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := cache.ValidateType(rType); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAddValue(t *testing.T) {
+	type TestAddValueTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestAddValueStruct struct {
+		Field int `test:"field"`
+	}
+	type TestAddReflectStruct struct {
+		Field int `test:"field"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestAddValueTag]("test")
+	if err := cache.AddValue(&TestAddValueStruct{}); err != nil {
+		t.Fatal("TestAddValue: failed value validation", err.Error())
+	}
+	var held any = TestAddReflectStruct{}
+	if err := cache.AddReflectValue(reflect.ValueOf(&held).Elem()); err != nil {
+		t.Fatal("TestAddValue: failed reflect value validation", err.Error())
+	}
+	if _, ok := cache.Get(reflect.TypeOf(TestAddValueStruct{})); !ok {
+		t.Error("TestAddValue: missing value type")
+	}
+	if _, ok := cache.Get(reflect.TypeOf(TestAddReflectStruct{})); !ok {
+		t.Error("TestAddValue: missing reflect value type")
+	}
+	if err := cache.AddValue(nil); err == nil {
+		t.Error("TestAddValue: failed nil value validation")
+	}
+	if err := cache.AddReflectValue(reflect.Value{}); err == nil {
+		t.Error("TestAddValue: failed invalid reflect value validation")
+	}
+}
+
+func BenchmarkAddValue(b *testing.B) {
+	cache, err := spectagular.NewFieldTagCache[benchmarkScalarTag]("test")
+	if err != nil {
+		b.Fatal(err)
+	}
+	v := reflect.ValueOf(benchmarkScalarStruct{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := cache.AddValue(v.Interface()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAddReflectValue(b *testing.B) {
+	cache, err := spectagular.NewFieldTagCache[benchmarkScalarTag]("test")
+	if err != nil {
+		b.Fatal(err)
+	}
+	v := reflect.ValueOf(benchmarkScalarStruct{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := cache.AddReflectValue(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestBareValues(t *testing.T) {
+	type TestBareTag struct {
+		Nullable bool   `structtag:"nullable"`
+		Kind     string `structtag:"kind,positional=1"`
+	}
+	type TestBareStruct struct {
+		Single   int `test:"justavalue"`
+		Flag     int `test:"nullable"`
+		Keyed    int `test:"nullable,kind=int,other"`
+		Multiple int `test:"first,second"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestBareTag]("test", reflect.TypeOf(TestBareStruct{}))
+	if err != nil {
+		t.Fatal("TestBareValues: failed bare tags validation", err.Error())
+	}
+	// unknown bare values are claimed by the first positional option that was not given
+	assertEqual(t, tags[0].Value.Kind, "justavalue", "TestBareValues: wrong single value:")
+	assertEqual(t, tags[1].Value.Nullable, true, "TestBareValues: wrong flag value:")
+	assertEqual(t, tags[1].Value.Kind, "", "TestBareValues: wrong flag value:")
+	assertEqual(t, tags[2].Value.Kind, "int", "TestBareValues: wrong keyed value:")
+	assertEqual(t, tags[3].Value.Kind, "second", "TestBareValues: wrong positional value:")
+	type TestNoPositionalTag struct {
+		Nullable bool `structtag:"nullable"`
+	}
+	type TestSingleStruct struct {
+		Single int `test:"justavalue"`
+	}
+	if _, err = spectagular.ParseTagsForType[TestNoPositionalTag]("test", reflect.TypeOf(TestSingleStruct{})); err != nil {
+		t.Error("TestBareValues: failed lenient bare value validation", err.Error())
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestNoPositionalTag]("test", spectagular.WithStrictBareValues())
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestSingleStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "justavalue") {
+		t.Error("TestBareValues: failed strict bare value validation", err)
+	}
+}
+
+type nameCollector[T any] struct {
+	names []string
+	limit int
+}
+
+func (n *nameCollector[T]) VisitField(index []int, name string, value T) error {
+	n.names = append(n.names, fmt.Sprintf("%v %s", index, name))
+	if len(n.names) == n.limit {
+		return spectagular.ErrStopWalk
+	}
+	return nil
+}
+
+func TestWalk(t *testing.T) {
+	type TestWalkTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestWalkEmbedded struct {
+		Inner string `test:"inner"`
+	}
+	type TestWalkStruct struct {
+		First string `test:"first"`
+		TestWalkEmbedded
+		Last string `test:"last"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestWalkTag]("test")
+	collector := &nameCollector[TestWalkTag]{}
+	if err := cache.Walk(reflect.TypeOf(TestWalkStruct{}), collector); err != nil {
+		t.Fatal("TestWalk: failed walk", err.Error())
+	}
+	assertEqual(t, strings.Join(collector.names, ","), "[0] First,[1 0] Inner,[2] Last", "TestWalk: wrong visited fields:")
+	collector = &nameCollector[TestWalkTag]{limit: 2}
+	if err := cache.Walk(reflect.TypeOf(TestWalkStruct{}), collector); err != nil {
+		t.Fatal("TestWalk: failed stopped walk", err.Error())
+	}
+	assertEqual(t, strings.Join(collector.names, ","), "[0] First,[1 0] Inner", "TestWalk: wrong visited fields:")
+}
+
+func TestWalkPointerEmbeds(t *testing.T) {
+	type TestWalkTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestWalkEmbedded struct {
+		Inner string `test:"inner"`
+	}
+	type TestWalkStruct struct {
+		First string `test:"first"`
+		*TestWalkEmbedded
+		Last string `test:"last"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestWalkTag]("test")
+	collector := &nameCollector[TestWalkTag]{}
+	if err := cache.Walk(reflect.TypeOf(TestWalkStruct{}), collector); err != nil {
+		t.Fatal("TestWalkPointerEmbeds: failed walk", err.Error())
+	}
+	assertEqual(t, strings.Join(collector.names, ","), "[0] First,[1 0] Inner,[2] Last", "TestWalkPointerEmbeds: wrong visited fields:")
+}
+
+func TestRange(t *testing.T) {
+	type TestRangeTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestRangeStruct struct {
+		First  string `test:"first"`
+		Second string `test:"second"`
+		Third  string `test:"third"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestRangeTag]("test")
+	names := make([]string, 0)
+	err := cache.Range(reflect.TypeOf(TestRangeStruct{}), func(tag spectagular.FieldTag[TestRangeTag]) bool {
+		names = append(names, tag.Value.Name)
+		return true
+	})
+	if err != nil {
+		t.Fatal("TestRange: failed range", err.Error())
+	}
+	assertEqual(t, strings.Join(names, ","), "first,second,third", "TestRange: wrong ranged fields:")
+	names = names[:0]
+	err = cache.Range(reflect.TypeOf(TestRangeStruct{}), func(tag spectagular.FieldTag[TestRangeTag]) bool {
+		names = append(names, tag.Value.Name)
+		return false
+	})
+	if err != nil {
+		t.Fatal("TestRange: failed stopped range", err.Error())
+	}
+	assertEqual(t, strings.Join(names, ","), "first", "TestRange: wrong stopped fields:")
+	if err := cache.Range(reflect.TypeOf(0), func(spectagular.FieldTag[TestRangeTag]) bool { return true }); err == nil {
+		t.Error("TestRange: failed non struct validation")
+	}
+}
+
+func TestDefaultTrue(t *testing.T) {
+	type TestDefaultTrueTag struct {
+		Cache    bool `structtag:"cache,defaulttrue"`
+		Nullable bool `structtag:"nullable"`
+	}
+	type TestDefaultTrueStruct struct {
+		Absent   int `test:"nullable"`
+		Disabled int `test:"cache=false"`
+		Negated  int `test:"!cache,nullable"`
+		Enabled  int `test:"cache,!nullable"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestDefaultTrueTag]("test", reflect.TypeOf(TestDefaultTrueStruct{}))
+	if err != nil {
+		t.Fatal("TestDefaultTrue: failed default true tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Cache, true, "TestDefaultTrue: wrong absent value:")
+	assertEqual(t, tags[0].Value.Nullable, true, "TestDefaultTrue: wrong flag value:")
+	assertEqual(t, tags[1].Value.Cache, false, "TestDefaultTrue: wrong disabled value:")
+	assertEqual(t, tags[2].Value.Cache, false, "TestDefaultTrue: wrong negated value:")
+	assertEqual(t, tags[2].Value.Nullable, true, "TestDefaultTrue: wrong flag value:")
+	assertEqual(t, tags[3].Value.Cache, true, "TestDefaultTrue: wrong enabled value:")
+	assertEqual(t, tags[3].Value.Nullable, false, "TestDefaultTrue: wrong negated value:")
+	type TestDefaultTrueInvalid struct {
+		Cache string `structtag:"cache,defaulttrue"`
+	}
+	if _, err = spectagular.NewFieldTagCache[TestDefaultTrueInvalid]("test"); err == nil {
+		t.Error("TestDefaultTrue: failed non bool validation")
+	}
+}
+
+func TestIgnorePrefix(t *testing.T) {
+	type TestIgnoreTag struct {
+		Name  string `structtag:"$name,required"`
+		Value int    `structtag:"value"`
+	}
+	type TestIgnoreStruct struct {
+		Parsed  int `test:"parsed,value=1"`
+		Ignored int `test:"//ignored,value=2"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestIgnoreTag]("test", spectagular.WithIgnorePrefix("//"))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestIgnoreStruct{}))
+	if err != nil {
+		t.Fatal("TestIgnorePrefix: failed ignored tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "parsed", "TestIgnorePrefix: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Value, 1, "TestIgnorePrefix: wrong parsed value:")
+	assertEqual(t, tags[1].FieldName, "Ignored", "TestIgnorePrefix: wrong ignored field:")
+	assertEqual(t, tags[1].Value.Name, "", "TestIgnorePrefix: wrong ignored value:")
+	assertEqual(t, tags[1].Value.Value, 0, "TestIgnorePrefix: wrong ignored value:")
+}
+
+func TestGetRaw(t *testing.T) {
+	type TestRawTag struct {
+		Name     string   `structtag:"$name"`
+		Value    int      `structtag:"value"`
+		List     []string `structtag:"list"`
+		Nullable bool     `structtag:"nullable"`
+	}
+	type TestRawStruct struct {
+		Field    int `test:"field,value=1,list=[a,'b c'],nullable,unknown"`
+		Empty    int
+		Repeated int `test:"repeated,list=a,list=[b,c],value=1,value=2"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestRawTag]("test")
+	if _, ok := cache.GetRaw(reflect.TypeOf(TestRawStruct{})); ok {
+		t.Error("TestGetRaw: found raw values for uncached type")
+	}
+	if err := cache.Add(reflect.TypeOf(TestRawStruct{})); err != nil {
+		t.Fatal("TestGetRaw: failed raw tags validation", err.Error())
+	}
+	raw, ok := cache.GetRaw(reflect.TypeOf(&TestRawStruct{}))
+	if !ok {
+		t.Fatal("TestGetRaw: missing raw values")
+	}
+	assertEqual(t, len(raw), 3, "TestGetRaw: wrong number of fields:")
+	assertEqual(t, len(raw["Field"]), 5, "TestGetRaw: wrong number of options:")
+	assertEqual(t, raw["Field"]["$name"], "field", "TestGetRaw: wrong raw value:")
+	assertEqual(t, raw["Field"]["value"], "1", "TestGetRaw: wrong raw value:")
+	assertEqual(t, raw["Field"]["list"], "a,'b c'", "TestGetRaw: wrong raw value:")
+	assertEqual(t, raw["Field"]["nullable"], "nullable", "TestGetRaw: wrong raw value:")
+	assertEqual(t, raw["Field"]["unknown"], "unknown", "TestGetRaw: wrong raw value:")
+	assertEqual(t, len(raw["Empty"]), 0, "TestGetRaw: wrong number of options:")
+	assertEqual(t, raw["Repeated"]["list"], "a,b,c", "TestGetRaw: wrong repeated raw value:")
+	assertEqual(t, raw["Repeated"]["value"], "2", "TestGetRaw: wrong repeated raw value:")
+}
+
+type testDefaultsTag struct {
+	Name    string    `structtag:"$name"`
+	Since   time.Time `structtag:"since"`
+	Retries int       `structtag:"retries,default=3"`
+	Mode    string    `structtag:"mode"`
+}
+
+func (d *testDefaultsTag) DefaultFor(option string) (string, bool) {
+	switch option {
+	case "since":
+		return "2024-01-02", true
+	case "retries":
+		return "5", true
+	}
+	return "", false
+}
+
+func TestDefaults(t *testing.T) {
+	type TestDefaultsStruct struct {
+		Absent int `test:"absent"`
+		Given  int `test:"given,since=2020-05-06,retries=1,mode=fast"`
+	}
+	tags, err := spectagular.ParseTagsForType[testDefaultsTag]("test", reflect.TypeOf(TestDefaultsStruct{}))
+	if err != nil {
+		t.Fatal("TestDefaults: failed default tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Since.Format("2006-01-02"), "2024-01-02", "TestDefaults: wrong dynamic default:")
+	// static defaults take precedence over dynamic defaults
+	assertEqual(t, tags[0].Value.Retries, 3, "TestDefaults: wrong static default:")
+	assertEqual(t, tags[0].Value.Mode, "", "TestDefaults: wrong value without default:")
+	assertEqual(t, tags[1].Value.Since.Format("2006-01-02"), "2020-05-06", "TestDefaults: wrong given value:")
+	assertEqual(t, tags[1].Value.Retries, 1, "TestDefaults: wrong given value:")
+	assertEqual(t, tags[1].Value.Mode, "fast", "TestDefaults: wrong given value:")
+	type TestDefaultTagTag struct {
+		Priority int      `structtag:"priority" default:"5"`
+		Labels   []string `structtag:"labels" default:"a,b"`
+		Inline   int      `structtag:"inline,default=1" default:"2"`
+	}
+	type TestDefaultTagStruct struct {
+		Absent int `test:""`
+		Given  int `test:"priority=1,labels=c"`
+	}
+	defaults, err := spectagular.ParseTagsForType[TestDefaultTagTag]("test", reflect.TypeOf(TestDefaultTagStruct{}))
+	if err != nil {
+		t.Fatal("TestDefaults: failed default struct tag validation", err.Error())
+	}
+	assertEqual(t, defaults[0].Value.Priority, 5, "TestDefaults: wrong struct tag default:")
+	assertEqual(t, strings.Join(defaults[0].Value.Labels, "|"), "a|b", "TestDefaults: wrong struct tag default:")
+	assertEqual(t, defaults[0].Value.Inline, 1, "TestDefaults: wrong inline default:")
+	assertEqual(t, defaults[1].Value.Priority, 1, "TestDefaults: wrong given value:")
+	assertEqual(t, strings.Join(defaults[1].Value.Labels, "|"), "c", "TestDefaults: wrong given value:")
+}
+
+func TestConflicts(t *testing.T) {
+	type TestConflictTag struct {
+		Inline string `structtag:"inline"`
+		File   string `structtag:"file,conflictswith=inline"`
+		Mode   string `structtag:"mode"`
+	}
+	type TestConflictSingle struct {
+		Inline int `test:"inline=abc,mode=fast"`
+		File   int `test:"file=a.txt,mode=fast"`
+	}
+	type TestConflictBoth struct {
+		Both int `test:"inline=abc,file=a.txt"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestConflictTag]("test")
+	if err != nil {
+		t.Fatal("TestConflicts: failed conflict validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestConflictSingle{}))
+	if err != nil {
+		t.Fatal("TestConflicts: failed single tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Inline, "abc", "TestConflicts: wrong parsed value:")
+	assertEqual(t, tags[1].Value.File, "a.txt", "TestConflicts: wrong parsed value:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestConflictBoth{}))
+	if err == nil || !strings.Contains(err.Error(), "'inline' and 'file'") {
+		t.Error("TestConflicts: failed conflicting tags validation", err)
+	}
+	type TestConflictUnknown struct {
+		File string `structtag:"file,conflictswith=missing"`
+	}
+	if _, err = spectagular.NewFieldTagCache[TestConflictUnknown]("test"); err == nil {
+		t.Error("TestConflicts: failed unknown conflict validation")
+	}
+}
+
+func TestOptionOrder(t *testing.T) {
+	type TestOrderTag struct {
+		Name     string `structtag:"$name"`
+		Value    int    `structtag:"value"`
+		Mode     string `structtag:"mode"`
+		Nullable bool   `structtag:"nullable"`
+	}
+	type TestOrderStruct struct {
+		Field   int `test:"field,nullable,mode=fast,value=1"`
+		Reverse int `test:"reverse,value=1,mode=fast"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestOrderTag]("test")
+	order, err := cache.OptionOrder(reflect.TypeOf(TestOrderStruct{}), "Field")
+	if err != nil {
+		t.Fatal("TestOptionOrder: failed option order", err.Error())
+	}
+	assertEqual(t, strings.Join(order, ","), "$name,nullable,mode,value", "TestOptionOrder: wrong option order:")
+	order, err = cache.OptionOrder(reflect.TypeOf(TestOrderStruct{}), "Reverse")
+	if err != nil {
+		t.Fatal("TestOptionOrder: failed option order", err.Error())
+	}
+	assertEqual(t, strings.Join(order, ","), "$name,value,mode", "TestOptionOrder: wrong option order:")
+	if _, err = cache.OptionOrder(reflect.TypeOf(TestOrderStruct{}), "Missing"); err == nil {
+		t.Error("TestOptionOrder: failed unknown field validation")
+	}
+}
+
+func TestUnknownValueMarker(t *testing.T) {
+	type TestMarkerTag struct {
+		Value    int           `structtag:"value"`
+		Timeout  time.Duration `structtag:"timeout"`
+		Required int           `structtag:"required"`
+	}
+	type TestMarkerStruct struct {
+		Bad  int `test:"value=abc,timeout=soon,required=1"`
+		Good int `test:"value=1,timeout=1s"`
+	}
+	marked := make([]string, 0)
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestMarkerTag]("test", spectagular.WithUnknownValueMarker(func(field reflect.StructField, raw string) {
+		marked = append(marked, field.Name+"="+raw)
+	}))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestMarkerStruct{}))
+	if err != nil {
+		t.Fatal("TestUnknownValueMarker: failed marker tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Value, 0, "TestUnknownValueMarker: wrong unknown value:")
+	assertEqual(t, tags[0].Value.Required, 1, "TestUnknownValueMarker: wrong parsed value:")
+	assertEqual(t, tags[1].Value.Value, 1, "TestUnknownValueMarker: wrong parsed value:")
+	assertEqual(t, strings.Join(marked, ","), "Bad=abc,Bad=soon", "TestUnknownValueMarker: wrong marked values:")
+}
+
+type testNameOpts struct {
+	Name     string `structtag:"$name"`
+	Optional bool   `structtag:"optional"`
+}
+
+type testValidationOpts struct {
+	Min int `structtag:"min"`
+	Max int `structtag:"max"`
+}
+
+func TestEmbeddedDefinitions(t *testing.T) {
+	type TestEmbeddedTag struct {
+		testNameOpts
+		testValidationOpts
+		Format string `structtag:"format"`
+	}
+	type TestEmbeddedStruct struct {
+		Field int `test:"field,optional,min=1,max=10,format=hex"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestEmbeddedTag]("test", reflect.TypeOf(TestEmbeddedStruct{}))
+	if err != nil {
+		t.Fatal("TestEmbeddedDefinitions: failed embedded tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "field", "TestEmbeddedDefinitions: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Optional, true, "TestEmbeddedDefinitions: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Min, 1, "TestEmbeddedDefinitions: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Max, 10, "TestEmbeddedDefinitions: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Format, "hex", "TestEmbeddedDefinitions: wrong parsed value:")
+	type TestEmbeddedDuplicate struct {
+		testValidationOpts
+		Min int `structtag:"min"`
+	}
+	if _, err = spectagular.NewFieldTagCache[TestEmbeddedDuplicate]("test"); err == nil {
+		t.Error("TestEmbeddedDefinitions: failed duplicate name validation")
+	}
+}
+
+func TestConcurrentCache(t *testing.T) {
+	type TestConcurrentTag struct {
+		Name  string `structtag:"$name"`
+		Value int    `structtag:"value"`
+	}
+	type TestConcurrentA struct {
+		Field int `test:"a,value=1"`
+	}
+	type TestConcurrentB struct {
+		Field int `test:"b,value=2"`
+	}
+	type TestConcurrentC struct {
+		Field int `test:"c,value=3"`
+	}
+	types := []reflect.Type{reflect.TypeOf(TestConcurrentA{}), reflect.TypeOf(&TestConcurrentB{}), reflect.TypeOf(TestConcurrentC{})}
+	cache, _ := spectagular.NewFieldTagCache[TestConcurrentTag]("test")
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rType := types[i%len(types)]
+			tags, err := cache.GetOrAdd(rType)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if tags[0].Value.Value != i%len(types)+1 {
+				errs <- errors.New("wrong parsed value")
+			}
+			cache.Get(rType)
+			cache.GetByName("github.com/matt1484/spectagular_test.TestConcurrentA")
+			cache.Lookup("value")
+			if i%10 == 0 {
+				cache.SetRequired("value", false)
+				cache.ValidateCache()
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error("TestConcurrentCache: failed concurrent access", err.Error())
+	}
+	for _, rType := range types {
+		if _, ok := cache.Get(rType); !ok {
+			t.Error("TestConcurrentCache: missing cached type", rType)
+		}
+	}
+}
+
+type testEmbeddedBase struct {
+	ID      int    `db:"id"`
+	Created string `db:"created_at"`
+}
+
+func TestEmbeddedFields(t *testing.T) {
+	type TestDBTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestTaggedBase struct {
+		Version int `db:"version"`
+	}
+	type TestUser struct {
+		testEmbeddedBase
+		Name           string `db:"name"`
+		TestTaggedBase `db:"base"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestDBTag]("db", reflect.TypeOf(TestUser{}))
+	if err != nil {
+		t.Fatal("TestEmbeddedFields: failed embedded tags validation", err.Error())
+	}
+	assertEqual(t, len(tags), 3, "TestEmbeddedFields: wrong number of fields:")
+	assertEqual(t, tags[0].Value.Name, "id", "TestEmbeddedFields: wrong parsed value:")
+	assertEqual(t, fmt.Sprint(tags[0].FieldIndexPath), "[0 0]", "TestEmbeddedFields: wrong field index path:")
+	assertEqual(t, tags[0].FieldIndex, 0, "TestEmbeddedFields: wrong field index:")
+	assertEqual(t, tags[1].Value.Name, "created_at", "TestEmbeddedFields: wrong parsed value:")
+	assertEqual(t, fmt.Sprint(tags[1].FieldIndexPath), "[0 1]", "TestEmbeddedFields: wrong field index path:")
+	assertEqual(t, tags[2].Value.Name, "name", "TestEmbeddedFields: wrong parsed value:")
+	assertEqual(t, fmt.Sprint(tags[2].FieldIndexPath), "[1]", "TestEmbeddedFields: wrong field index path:")
+	user := TestUser{}
+	user.ID = 7
+	assertEqual(t, reflect.ValueOf(user).FieldByIndex(tags[0].FieldIndexPath).Interface().(int), 7, "TestEmbeddedFields: wrong field for path:")
+}
+
+type testEmbeddedNode struct {
+	*testEmbeddedNode
+	Value string `db:"value"`
+}
+
+func TestEmbeddedPointerFields(t *testing.T) {
+	type TestDBTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestUser struct {
+		*testEmbeddedBase
+		Name string `db:"name"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestDBTag]("db", reflect.TypeOf(TestUser{}))
+	if err != nil {
+		t.Fatal("TestEmbeddedPointerFields: failed embedded tags validation", err.Error())
+	}
+	assertEqual(t, len(tags), 3, "TestEmbeddedPointerFields: wrong number of fields:")
+	assertEqual(t, tags[0].Value.Name, "id", "TestEmbeddedPointerFields: wrong parsed value:")
+	assertEqual(t, fmt.Sprint(tags[0].FieldIndexPath), "[0 0]", "TestEmbeddedPointerFields: wrong field index path:")
+	assertEqual(t, tags[2].Value.Name, "name", "TestEmbeddedPointerFields: wrong parsed value:")
+	user := TestUser{testEmbeddedBase: &testEmbeddedBase{ID: 7}}
+	assertEqual(t, reflect.ValueOf(user).FieldByIndex(tags[0].FieldIndexPath).Interface().(int), 7, "TestEmbeddedPointerFields: wrong field for path:")
+	tags, err = spectagular.ParseTagsForType[TestDBTag]("db", reflect.TypeOf(testEmbeddedNode{}))
+	if err != nil {
+		t.Fatal("TestEmbeddedPointerFields: failed self embedded tags validation", err.Error())
+	}
+	assertEqual(t, len(tags), 1, "TestEmbeddedPointerFields: wrong number of self embedded fields:")
+}
+
+func TestSecretProvider(t *testing.T) {
+	type TestSecretTag struct {
+		User     string `structtag:"user"`
+		Password string `structtag:"password"`
+		Port     int    `structtag:"port"`
+	}
+	type TestSecretStruct struct {
+		Field int `test:"user=admin,password=secret:db_password,port=secret:db_port"`
+	}
+	type TestMissingSecretStruct struct {
+		Field int `test:"password=secret:missing"`
+	}
+	secrets := map[string]string{"db_password": "hunter2", "db_port": "5432"}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestSecretTag]("test", spectagular.WithSecretProvider(func(ref string) (string, error) {
+		secret, ok := secrets[ref]
+		if !ok {
+			return "", errors.New("unknown secret")
+		}
+		return secret, nil
+	}))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestSecretStruct{}))
+	if err != nil {
+		t.Fatal("TestSecretProvider: failed secret tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.User, "admin", "TestSecretProvider: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Password, "hunter2", "TestSecretProvider: wrong secret:")
+	assertEqual(t, tags[0].Value.Port, 5432, "TestSecretProvider: wrong secret:")
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestMissingSecretStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "missing") || !strings.Contains(err.Error(), "Field") {
+		t.Error("TestSecretProvider: failed missing secret validation", err)
+	}
+	tags, err = spectagular.ParseTagsForType[TestSecretTag]("test", reflect.TypeOf(TestSecretStruct{}))
+	if err != nil {
+		t.Fatal("TestSecretProvider: failed default tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Password, "secret:db_password", "TestSecretProvider: wrong value without provider:")
+}
+
+func TestGetGrouped(t *testing.T) {
+	type TestGroupTag struct {
+		Name  string `structtag:"$name"`
+		Group string `structtag:"group"`
+	}
+	type TestGroupStruct struct {
+		Host    string `test:"host"`
+		Retries int    `test:"retries,group=advanced"`
+		Timeout int    `test:"timeout,group=advanced"`
+		Debug   bool   `test:"debug,group=dev"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestGroupTag]("test")
+	groups, err := cache.GetGrouped(reflect.TypeOf(TestGroupStruct{}))
+	if err != nil {
+		t.Fatal("TestGetGrouped: failed grouped tags validation", err.Error())
+	}
+	assertEqual(t, len(groups), 3, "TestGetGrouped: wrong number of groups:")
+	assertEqual(t, len(groups[""]), 1, "TestGetGrouped: wrong default group:")
+	assertEqual(t, groups[""][0].FieldName, "Host", "TestGetGrouped: wrong default group:")
+	assertEqual(t, len(groups["advanced"]), 2, "TestGetGrouped: wrong advanced group:")
+	assertEqual(t, groups["advanced"][0].FieldName, "Retries", "TestGetGrouped: wrong advanced group:")
+	assertEqual(t, groups["advanced"][1].FieldName, "Timeout", "TestGetGrouped: wrong advanced group:")
+	assertEqual(t, groups["dev"][0].FieldName, "Debug", "TestGetGrouped: wrong dev group:")
+	type TestNoGroupTag struct {
+		Name string `structtag:"$name"`
+	}
+	noGroupCache, _ := spectagular.NewFieldTagCache[TestNoGroupTag]("test")
+	ungrouped, err := noGroupCache.GetGrouped(reflect.TypeOf(TestGroupStruct{}))
+	if err != nil {
+		t.Fatal("TestGetGrouped: failed ungrouped tags validation", err.Error())
+	}
+	assertEqual(t, len(ungrouped[""]), 4, "TestGetGrouped: wrong default group:")
+}
+
+func TestClear(t *testing.T) {
+	type TestClearTag struct {
+		Name string `structtag:"$name,required"`
+	}
+	type TestClearA struct {
+		Field int `test:"a"`
+	}
+	type TestClearB struct {
+		Field int `test:"b"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestClearTag]("test")
+	for _, rType := range []reflect.Type{reflect.TypeOf(TestClearA{}), reflect.TypeOf(TestClearB{})} {
+		if err := cache.Add(rType); err != nil {
+			t.Fatal("TestClear: failed tags validation", err.Error())
+		}
+	}
+	cache.Clear()
+	if _, ok := cache.Get(reflect.TypeOf(TestClearA{})); ok {
+		t.Error("TestClear: found type after clear")
+	}
+	if _, ok := cache.GetByName("github.com/matt1484/spectagular_test.TestClearB"); ok {
+		t.Error("TestClear: found type by name after clear")
+	}
+	if err := cache.Add(reflect.TypeOf(TestClearA{})); err != nil {
+		t.Fatal("TestClear: failed tags validation after clear", err.Error())
+	}
+	tags, ok := cache.Get(reflect.TypeOf(TestClearA{}))
+	if !ok {
+		t.Fatal("TestClear: missing type added after clear")
+	}
+	assertEqual(t, tags[0].Value.Name, "a", "TestClear: wrong parsed value:")
+}
+
+func TestDottedNames(t *testing.T) {
+	type TestDottedTag struct {
+		Name  string `structtag:"$name"`
+		Alias string `structtag:"alias"`
+	}
+	type TestDottedStruct struct {
+		Host string `test:"db.host,alias=database.primary.host"`
+		Port int    `test:"port"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestDottedTag]("test", reflect.TypeOf(TestDottedStruct{}))
+	if err != nil {
+		t.Fatal("TestDottedNames: failed dotted tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "db.host", "TestDottedNames: wrong dotted name:")
+	assertEqual(t, tags[0].Value.Alias, "database.primary.host", "TestDottedNames: wrong dotted value:")
+	assertEqual(t, strings.Join(spectagular.SplitNamePath(tags[0].Value.Name), "/"), "db/host", "TestDottedNames: wrong path:")
+	assertEqual(t, len(spectagular.SplitNamePath(tags[0].Value.Alias)), 3, "TestDottedNames: wrong path length:")
+	assertEqual(t, strings.Join(spectagular.SplitNamePath(tags[1].Value.Name), "/"), "port", "TestDottedNames: wrong path:")
+	assertEqual(t, len(spectagular.SplitNamePath("")), 0, "TestDottedNames: wrong empty path length:")
+}
+
+func TestAllowedTypes(t *testing.T) {
+	type TestAllowedTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestAllowed struct {
+		Field int `test:"allowed"`
+	}
+	type TestDisallowed struct {
+		Field int `test:"disallowed"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestAllowedTag]("test", spectagular.WithAllowedTypes(func(rType reflect.Type) bool {
+		return rType != reflect.TypeOf(TestDisallowed{})
+	}))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(&TestAllowed{}))
+	if err != nil {
+		t.Fatal("TestAllowedTypes: failed allowed tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "allowed", "TestAllowedTypes: wrong parsed value:")
+	if _, err = cache.GetOrAdd(reflect.TypeOf(TestDisallowed{})); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Error("TestAllowedTypes: failed disallowed type validation", err)
+	}
+	if err = cache.Add(reflect.TypeOf(TestDisallowed{})); err == nil {
+		t.Error("TestAllowedTypes: failed disallowed type validation")
+	}
+	if _, ok := cache.Get(reflect.TypeOf(TestDisallowed{})); ok {
+		t.Error("TestAllowedTypes: cached disallowed type")
+	}
+}
+
+func TestLen(t *testing.T) {
+	type TestLenTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestLenA struct {
+		Field int `test:"a"`
+	}
+	type TestLenB struct {
+		Field int `test:"b"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestLenTag]("test")
+	assertEqual(t, cache.Len(), 0, "TestLen: wrong initial length:")
+	cache.Add(reflect.TypeOf(TestLenA{}))
+	assertEqual(t, cache.Len(), 1, "TestLen: wrong length after add:")
+	cache.Add(reflect.TypeOf(TestLenB{}))
+	assertEqual(t, cache.Len(), 2, "TestLen: wrong length after add:")
+	cache.GetOrAdd(reflect.TypeOf(&TestLenA{}))
+	assertEqual(t, cache.Len(), 2, "TestLen: wrong length after repeated add:")
+}
+
+func TestTypes(t *testing.T) {
+	type TestTypesTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestTypesA struct {
+		Field int `test:"a"`
+	}
+	type TestTypesB struct {
+		Field int `test:"b"`
+	}
+	type TestTypesC struct {
+		Field int `test:"c"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestTypesTag]("test")
+	expected := []reflect.Type{reflect.TypeOf(TestTypesA{}), reflect.TypeOf(TestTypesB{}), reflect.TypeOf(TestTypesC{})}
+	for _, rType := range expected {
+		cache.Add(rType)
+	}
+	types := cache.Types()
+	assertEqual(t, len(types), 3, "TestTypes: wrong number of types:")
+	found := make(map[reflect.Type]bool)
+	for _, rType := range types {
+		found[rType] = true
+	}
+	for _, rType := range expected {
+		assertEqual(t, found[rType], true, "TestTypes: missing type:")
+	}
+	types[0] = nil
+	assertEqual(t, len(cache.Types()), 3, "TestTypes: wrong number of types after mutation:")
+	if _, ok := cache.Get(expected[0]); !ok {
+		t.Error("TestTypes: missing cached type after mutation")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	type TestProfileTag struct {
+		Host string `structtag:"host"`
+		Port int    `structtag:"port"`
+	}
+	type TestProfileStruct struct {
+		Grouped   int `test:"prod:{host=p,port=80};dev:{host=d}"`
+		Quoted    int `test:"dev:{host='{d}'}; prod:{host='p}'}"`
+		Missing   int `test:"dev:{host=d,port=8080}"`
+		Ungrouped int `test:"host=all,port=1"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestProfileTag]("test", spectagular.WithProfile("prod"))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestProfileStruct{}))
+	if err != nil {
+		t.Fatal("TestProfiles: failed profile tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Host, "p", "TestProfiles: wrong profile value:")
+	assertEqual(t, tags[0].Value.Port, 80, "TestProfiles: wrong profile value:")
+	assertEqual(t, tags[1].Value.Host, "p}", "TestProfiles: wrong quoted profile value:")
+	assertEqual(t, tags[2].Value.Host, "", "TestProfiles: wrong missing profile value:")
+	assertEqual(t, tags[2].Value.Port, 0, "TestProfiles: wrong missing profile value:")
+	assertEqual(t, tags[3].Value.Host, "all", "TestProfiles: wrong ungrouped value:")
+	type TestProfileInvalid struct {
+		Unclosed int `test:"prod:{host=p"`
+	}
+	if _, err = cache.GetOrAdd(reflect.TypeOf(TestProfileInvalid{})); err == nil {
+		t.Error("TestProfiles: failed missing brace validation")
+	}
+}
+
+func TestMarshalTagValue(t *testing.T) {
+	type TestMarshalTag struct {
+		Name     string        `structtag:"$name"`
+		Value    int           `structtag:"value"`
+		Desc     string        `structtag:"desc"`
+		List     []string      `structtag:"list"`
+		Timeout  time.Duration `structtag:"timeout"`
+		Nullable bool          `structtag:"nullable"`
+		Enabled  bool          `structtag:"enabled,defaulttrue"`
+		Unused   string        `structtag:"unused"`
+	}
+	type TestMarshalStruct struct {
+		Field int `test:"field,desc='a, b',list=[a,'b,c',d],timeout=1m30s,nullable,!enabled,value=2"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestMarshalTag]("test")
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestMarshalStruct{}))
+	if err != nil {
+		t.Fatal("TestMarshalTagValue: failed marshal tags validation", err.Error())
+	}
+	tag, err := cache.MarshalTagValue(tags[0].Value)
+	if err != nil {
+		t.Fatal("TestMarshalTagValue: failed to marshal value", err.Error())
+	}
+	assertEqual(t, tag, "field,value=2,desc='a, b',list=[a,'b,c',d],timeout=1m30s,nullable,!enabled", "TestMarshalTagValue: wrong tag:")
+	// the marshaled tag should parse back into the same value
+	remarshaled := reflect.StructOf([]reflect.StructField{{
+		Name: "Field",
+		Type: reflect.TypeOf(0),
+		Tag:  reflect.StructTag(`test:"` + tag + `"`),
+	}})
+	retags, err := cache.GetOrAdd(remarshaled)
+	if err != nil {
+		t.Fatal("TestMarshalTagValue: failed to parse marshaled tag", err.Error())
+	}
+	if !reflect.DeepEqual(tags[0].Value, retags[0].Value) {
+		t.Errorf("TestMarshalTagValue: wrong value after round trip: %v != %v", retags[0].Value, tags[0].Value)
+	}
+}
+
+func TestMarshalTagValueEscapes(t *testing.T) {
+	type TestMarshalTag struct {
+		Name  string                     `structtag:"$name"`
+		Brace string                     `structtag:"brace"`
+		Slash string                     `structtag:"slash"`
+		List  []string                   `structtag:"list"`
+		Range spectagular.Range[int]     `structtag:"range"`
+		Open  spectagular.Range[float64] `structtag:"open"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestMarshalTag]("test")
+	value := TestMarshalTag{
+		Name:  "field",
+		Brace: "{x}",
+		Slash: `a\\b`,
+		List:  []string{`c\d`, "{y}"},
+		Range: spectagular.Range[int]{Lo: 1, Hi: 10, HasLo: true, HasHi: true},
+		Open:  spectagular.Range[float64]{Lo: 0.5, HasLo: true},
+	}
+	tag, err := cache.MarshalTagValue(value)
+	if err != nil {
+		t.Fatal("TestMarshalTagValueEscapes: failed to marshal value", err.Error())
+	}
+	assertEqual(t, tag, `field,brace='{x}',slash=a\\\\b,list=[c\\d,'{y}'],range=1:10,open=0.5:`, "TestMarshalTagValueEscapes: wrong tag:")
+	remarshaled := reflect.StructOf([]reflect.StructField{{
+		Name: "Field",
+		Type: reflect.TypeOf(0),
+		Tag:  reflect.StructTag(`test:"` + strings.ReplaceAll(tag, `\`, `\\`) + `"`),
+	}})
+	retags, err := cache.GetOrAdd(remarshaled)
+	if err != nil {
+		t.Fatal("TestMarshalTagValueEscapes: failed to parse marshaled tag", err.Error())
+	}
+	if !reflect.DeepEqual(value, retags[0].Value) {
+		t.Errorf("TestMarshalTagValueEscapes: wrong value after round trip: %v != %v", retags[0].Value, value)
+	}
+}
+
+func TestMarshalTagValueRoundTrip(t *testing.T) {
+	type TestRoundTripTag struct {
+		Name  string            `structtag:"$name"`
+		List  []string          `structtag:"list"`
+		Map   map[string]string `structtag:"map"`
+		Count int               `structtag:"count,default=5"`
+		Flag  bool              `structtag:"flag" default:"true"`
+	}
+	type TestRoundTripTailTag struct {
+		Name string `structtag:"$name"`
+		Kind string `structtag:"kind,positional=1"`
+		Rest string `structtag:"$tail"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestRoundTripTag]("test")
+	tailCache, _ := spectagular.NewFieldTagCache[TestRoundTripTailTag]("test")
+	parse := func(tag string) reflect.Type {
+		return reflect.StructOf([]reflect.StructField{{
+			Name: "Field",
+			Type: reflect.TypeOf(0),
+			Tag:  reflect.StructTag(`test:"` + strings.ReplaceAll(tag, `\`, `\\`) + `"`),
+		}})
+	}
+	tests := []struct {
+		name  string
+		value TestRoundTripTag
+	}{
+		{"bracket in list", TestRoundTripTag{Name: "field", List: []string{"a]b", "c"}}},
+		{"brace in map", TestRoundTripTag{Name: "field", Map: map[string]string{"k": "v}x"}}},
+		{"trailing backslash", TestRoundTripTag{Name: "field", List: []string{"b", `a\`}}},
+		{"zero with default", TestRoundTripTag{Name: "field", Count: 0}},
+		{"false with default", TestRoundTripTag{Name: "field", Count: 1, Flag: false}},
+	}
+	for _, test := range tests {
+		tag, err := cache.MarshalTagValue(test.value)
+		if err != nil {
+			t.Errorf("TestMarshalTagValueRoundTrip: %s: failed to marshal value: %v", test.name, err)
+			continue
+		}
+		tags, err := cache.GetOrAdd(parse(tag))
+		if err != nil {
+			t.Errorf("TestMarshalTagValueRoundTrip: %s: failed to parse marshaled tag %s: %v", test.name, tag, err)
+			continue
+		}
+		if !reflect.DeepEqual(tags[0].Value, test.value) {
+			t.Errorf("TestMarshalTagValueRoundTrip: %s: wrong value after round trip of %s: %v != %v", test.name, tag, tags[0].Value, test.value)
+		}
+	}
+	tailTests := []struct {
+		name  string
+		value TestRoundTripTailTag
+	}{
+		{"tail", TestRoundTripTailTag{Name: "n", Kind: "x=1", Rest: "free text,more"}},
+		{"empty positional", TestRoundTripTailTag{Name: "n", Rest: "free text"}},
+		{"no tail", TestRoundTripTailTag{Name: "n"}},
+	}
+	for _, test := range tailTests {
+		tag, err := tailCache.MarshalTagValue(test.value)
+		if err != nil {
+			t.Errorf("TestMarshalTagValueRoundTrip: %s: failed to marshal value: %v", test.name, err)
+			continue
+		}
+		tags, err := tailCache.GetOrAdd(parse(tag))
+		if err != nil {
+			t.Errorf("TestMarshalTagValueRoundTrip: %s: failed to parse marshaled tag %s: %v", test.name, tag, err)
+			continue
+		}
+		if !reflect.DeepEqual(tags[0].Value, test.value) {
+			t.Errorf("TestMarshalTagValueRoundTrip: %s: wrong value after round trip of %s: %v != %v", test.name, tag, tags[0].Value, test.value)
+		}
+	}
+	type TestRoundTripKeyedTailTag struct {
+		Name string `structtag:"$name"`
+		X    int    `structtag:"x"`
+		Rest string `structtag:"$tail"`
+	}
+	keyedCache, _ := spectagular.NewFieldTagCache[TestRoundTripKeyedTailTag]("test")
+	if _, err := keyedCache.MarshalTagValue(TestRoundTripKeyedTailTag{Name: "n", X: 1, Rest: "free text"}); err == nil {
+		t.Error("TestMarshalTagValueRoundTrip: failed keyed option before tail validation")
+	}
+}
+
+func TestSeparator(t *testing.T) {
+	type TestSeparatorTag struct {
+		Name     string   `structtag:"$name"`
+		Column   string   `structtag:"column"`
+		List     []string `structtag:"list"`
+		Nullable bool     `structtag:"nullable"`
+	}
+	type TestSeparatorStruct struct {
+		Field int `test:"field;column='a;b';list=[a;'b,c';d];nullable"`
+		Other int `test:";column=a,b"`
+	}
+	cache, err := spectagular.NewFieldTagCacheWithOptions[TestSeparatorTag]("test", spectagular.WithSeparator(';'))
+	if err != nil {
+		t.Fatal("TestSeparator: failed to create cache", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestSeparatorStruct{}))
+	if err != nil {
+		t.Fatal("TestSeparator: failed separator tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "field", "TestSeparator: wrong name:")
+	assertEqual(t, tags[0].Value.Column, "a;b", "TestSeparator: wrong column:")
+	assertEqual(t, strings.Join(tags[0].Value.List, "|"), "a|b,c|d", "TestSeparator: wrong list:")
+	assertEqual(t, tags[0].Value.Nullable, true, "TestSeparator: wrong nullable:")
+	assertEqual(t, tags[1].Value.Name, "Other", "TestSeparator: wrong name:")
+	assertEqual(t, tags[1].Value.Column, "a,b", "TestSeparator: wrong column:")
+	tag, err := cache.MarshalTagValue(tags[0].Value)
+	if err != nil {
+		t.Fatal("TestSeparator: failed to marshal value", err.Error())
+	}
+	assertEqual(t, tag, "field;column='a;b';list=[a;b,c;d];nullable", "TestSeparator: wrong marshaled tag:")
+}
+
+func TestFloatEdgeCases(t *testing.T) {
+	type TestFloatTag struct {
+		F32  float32  `structtag:"f32"`
+		F64  float64  `structtag:"f64"`
+		PF32 *float32 `structtag:"pf32"`
+		PF64 *float64 `structtag:"pf64"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestFloatTag]("test")
+	values := []struct {
+		value string
+		f32   float32
+		f64   float64
+	}{
+		{"-0.0", float32(math.Copysign(0, -1)), math.Copysign(0, -1)},
+		{"0x1p-2", 0.25, 0.25},
+		{"0x1.8p1", 3, 3},
+		{"1e-45", math.SmallestNonzeroFloat32, 1e-45},
+		{"3.4028235e38", math.MaxFloat32, 3.4028235e38},
+		{"0.1", 0.1, 0.1},
+		{"-1.5E+3", -1500, -1500},
+	}
+	for _, v := range values {
+		field := reflect.StructField{
+			Name: "Field",
+			Type: reflect.TypeOf(0),
+			Tag:  reflect.StructTag(fmt.Sprintf(`test:"f32=%[1]s,f64=%[1]s,pf32=%[1]s,pf64=%[1]s"`, v.value)),
+		}
+		tags, err := cache.GetOrAdd(reflect.StructOf([]reflect.StructField{field}))
+		if err != nil {
+			t.Fatal("TestFloatEdgeCases: failed float tags validation", err.Error())
+		}
+		value := tags[0].Value
+		if math.Float32bits(value.F32) != math.Float32bits(v.f32) || math.Float32bits(*value.PF32) != math.Float32bits(v.f32) {
+			t.Errorf("TestFloatEdgeCases: wrong float32 for %s: %v, %v != %v", v.value, value.F32, *value.PF32, v.f32)
+		}
+		if math.Float64bits(value.F64) != math.Float64bits(v.f64) || math.Float64bits(*value.PF64) != math.Float64bits(v.f64) {
+			t.Errorf("TestFloatEdgeCases: wrong float64 for %s: %v, %v != %v", v.value, value.F64, *value.PF64, v.f64)
+		}
+	}
+	type TestFloatRangeTag struct {
+		F32 float32 `structtag:"f32,required"`
+	}
+	type TestFloatRangeStruct struct {
+		Field int `test:"f32=1e39"`
+	}
+	_, err := spectagular.ParseTagsForType[TestFloatRangeTag]("test", reflect.TypeOf(TestFloatRangeStruct{}))
+	if err == nil {
+		t.Error("TestFloatEdgeCases: failed float32 range validation")
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	type TestUnsupportedTag struct {
+		Fn func() `structtag:"fn"`
+	}
+	_, err := spectagular.NewFieldTagCache[TestUnsupportedTag]("test")
+	if !errors.Is(err, spectagular.ErrUnsupportedType) {
+		t.Error("TestTypedErrors: expected ErrUnsupportedType", err)
+	}
+	type TestDuplicateTag struct {
+		A string `structtag:"value"`
+		B string `structtag:"value"`
+	}
+	_, err = spectagular.NewFieldTagCache[TestDuplicateTag]("test")
+	if !errors.Is(err, spectagular.ErrDuplicateTagName) {
+		t.Error("TestTypedErrors: expected ErrDuplicateTagName", err)
+	}
+	assertEqual(t, err.Error(), "tag 'value' is in use by multiple fields", "TestTypedErrors: duplicate message:")
+	type TestRequiredTag struct {
+		Value int `structtag:"value,required"`
+	}
+	type TestMissingStruct struct {
+		Field int `test:"other=1"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestRequiredTag]("test")
+	err = cache.Add(reflect.TypeOf(TestMissingStruct{}))
+	if !errors.Is(err, spectagular.ErrMissingRequired) || !strings.Contains(err.Error(), "missing required tag fields: [value]") {
+		t.Error("TestTypedErrors: expected ErrMissingRequired", err)
+	}
+	type TestConversionStruct struct {
+		Field int `test:"value=abc"`
+	}
+	err = cache.Add(reflect.TypeOf(TestConversionStruct{}))
+	var conversionErr *spectagular.ConversionError
+	if !errors.As(err, &conversionErr) {
+		t.Fatal("TestTypedErrors: expected ConversionError", err)
+	}
+	assertEqual(t, conversionErr.FieldName, "Field", "TestTypedErrors: wrong conversion field name:")
+	assertEqual(t, conversionErr.Option, "value", "TestTypedErrors: wrong conversion option:")
+	assertEqual(t, conversionErr.TargetType.String(), "int", "TestTypedErrors: wrong conversion target type:")
+	assertEqual(t, conversionErr.Value, "abc", "TestTypedErrors: wrong conversion value:")
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Error("TestTypedErrors: conversion error does not wrap the resolver error", err)
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	type TestRecoveryTag struct {
+		Value   int           `structtag:"value,required"`
+		Timeout time.Duration `structtag:"timeout,required"`
+		Other   int           `structtag:"other,required"`
+	}
+	type TestRecoveryStruct struct {
+		Field int `test:"value=abc,timeout=soon,other=1"`
+	}
+	recovered := make([]string, 0)
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestRecoveryTag]("test", spectagular.WithErrorRecovery(func(field reflect.StructField, key, raw string, err error) (reflect.Value, bool) {
+		recovered = append(recovered, key+"="+raw)
+		if key == "value" {
+			return reflect.ValueOf(42), true
+		}
+		return reflect.Value{}, false
+	}))
+	_, err := cache.GetOrAdd(reflect.TypeOf(TestRecoveryStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "soon") {
+		t.Error("TestErrorRecovery: failed unrecovered value validation", err)
+	}
+	type TestRecoveredStruct struct {
+		Field int `test:"value=abc,timeout=1s,other=1"`
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestRecoveredStruct{}))
+	if err != nil {
+		t.Fatal("TestErrorRecovery: failed recovered tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Value, 42, "TestErrorRecovery: wrong recovered value:")
+	assertEqual(t, tags[0].Value.Timeout, time.Second, "TestErrorRecovery: wrong parsed value:")
+	assertEqual(t, tags[0].Value.Other, 1, "TestErrorRecovery: wrong parsed value:")
+	assertEqual(t, strings.Join(recovered, ","), "value=abc,timeout=soon,value=abc", "TestErrorRecovery: wrong recovered options:")
+	type TestRecoveryBoundsTag struct {
+		Workers int `structtag:"workers" max:"10"`
+	}
+	boundsCache, _ := spectagular.NewFieldTagCacheWithOptions[TestRecoveryBoundsTag]("test", spectagular.WithErrorRecovery(func(field reflect.StructField, key, raw string, err error) (reflect.Value, bool) {
+		return reflect.ValueOf(42), true
+	}))
+	type TestRecoveredBounds struct {
+		Field int `test:"workers=many"`
+	}
+	if _, err := boundsCache.GetOrAdd(reflect.TypeOf(TestRecoveredBounds{})); err == nil || !strings.Contains(err.Error(), "max 10") {
+		t.Error("TestErrorRecovery: failed recovered max validation", err)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	type TestTemplateTag struct {
+		Name    string            `structtag:"$name"`
+		Host    string            `structtag:"host"`
+		Port    int               `structtag:"port"`
+		Secure  bool              `structtag:"secure"`
+		Headers map[string]string `structtag:"headers"`
+	}
+	type TestTemplateStruct struct {
+		Expanded int `test:"expanded,@defaults,port=8080"`
+		Keyed    int `test:"keyed,headers=@headers"`
+		Nested   int `test:"nested,@secure"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestTemplateTag](
+		"test",
+		spectagular.WithTemplate("defaults", "host=localhost,port=80"),
+		spectagular.WithTemplate("headers", "a=1,b=2"),
+		spectagular.WithTemplate("secure", "@defaults,secure"),
+		spectagular.WithTemplate("loop", "@cycle"),
+		spectagular.WithTemplate("cycle", "port=1,@loop"),
+	)
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestTemplateStruct{}))
+	if err != nil {
+		t.Fatal("TestTemplates: failed template tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "expanded", "TestTemplates: wrong name:")
+	assertEqual(t, tags[0].Value.Host, "localhost", "TestTemplates: wrong expanded value:")
+	assertEqual(t, tags[0].Value.Port, 8080, "TestTemplates: wrong overridden value:")
+	assertEqual(t, tags[1].Value.Headers["b"], "2", "TestTemplates: wrong keyed template value:")
+	assertEqual(t, tags[2].Value.Host, "localhost", "TestTemplates: wrong nested template value:")
+	assertEqual(t, tags[2].Value.Secure, true, "TestTemplates: wrong nested template value:")
+	type TestTemplateCycle struct {
+		Field int `test:"field,@loop"`
+	}
+	_, err = cache.GetOrAdd(reflect.TypeOf(TestTemplateCycle{}))
+	if err == nil || !strings.Contains(err.Error(), "references itself") {
+		t.Error("TestTemplates: failed template cycle validation", err)
+	}
+}
+
+func TestRawTags(t *testing.T) {
+	type TestRawTagsTag struct {
+		Name  string `structtag:"$name"`
+		Value int    `structtag:"value"`
+	}
+	type TestRawTagsStruct struct {
+		Field   int `test:"field,value=1, extra='a,b'"`
+		Ignored int `test:"//skip"`
+		Empty   int
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestRawTagsTag]("test", spectagular.WithIgnorePrefix("//"))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestRawTagsStruct{}))
+	if err != nil {
+		t.Fatal("TestRawTags: failed raw tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Raw, "field,value=1, extra='a,b'", "TestRawTags: wrong raw tag:")
+	assertEqual(t, tags[1].Raw, "//skip", "TestRawTags: wrong raw tag:")
+	assertEqual(t, tags[2].Raw, "", "TestRawTags: wrong raw tag:")
+}
+
+func TestGetField(t *testing.T) {
+	type TestGetFieldTag struct {
+		Name  string `structtag:"$name"`
+		Value int    `structtag:"value"`
+	}
+	type TestGetFieldStruct struct {
+		First  int `test:"first,value=1"`
+		Second int `test:"second,value=2"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestGetFieldTag]("test")
+	tag, ok := cache.GetField(reflect.TypeOf(TestGetFieldStruct{}), "Second")
+	if !ok {
+		t.Fatal("TestGetField: missing field")
+	}
+	assertEqual(t, tag.FieldName, "Second", "TestGetField: wrong field name:")
+	assertEqual(t, tag.Value.Name, "second", "TestGetField: wrong parsed name:")
+	assertEqual(t, tag.Value.Value, 2, "TestGetField: wrong parsed value:")
+	if _, ok := cache.GetField(reflect.TypeOf(TestGetFieldStruct{}), "Third"); ok {
+		t.Error("TestGetField: found unknown field")
+	}
+}
+
+func TestGetByTagName(t *testing.T) {
+	type TestGetByTagNameTag struct {
+		Name  string `structtag:"$name"`
+		Value int    `structtag:"value"`
+	}
+	type TestGetByTagNameStruct struct {
+		UserID   int `test:"user_id,value=1"`
+		Email    int `test:"email,value=2"`
+		Untagged int `test:",value=3"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestGetByTagNameTag]("test")
+	tag, ok := cache.GetByTagName(reflect.TypeOf(TestGetByTagNameStruct{}), "email")
+	if !ok {
+		t.Fatal("TestGetByTagName: missing field")
+	}
+	assertEqual(t, tag.FieldName, "Email", "TestGetByTagName: wrong field name:")
+	assertEqual(t, tag.Value.Value, 2, "TestGetByTagName: wrong parsed value:")
+	tag, ok = cache.GetByTagName(reflect.TypeOf(TestGetByTagNameStruct{}), "Untagged")
+	if !ok {
+		t.Fatal("TestGetByTagName: missing field with default name")
+	}
+	assertEqual(t, tag.Value.Value, 3, "TestGetByTagName: wrong parsed value:")
+	if _, ok := cache.GetByTagName(reflect.TypeOf(TestGetByTagNameStruct{}), "Email"); ok {
+		t.Error("TestGetByTagName: found field by its go name")
+	}
+	type TestNamelessTag struct {
+		Value int `structtag:"value"`
+	}
+	nameless, _ := spectagular.NewFieldTagCache[TestNamelessTag]("test")
+	if _, ok := nameless.GetByTagName(reflect.TypeOf(TestGetByTagNameStruct{}), "email"); ok {
+		t.Error("TestGetByTagName: found field without a $name option")
+	}
+}
+
+type testTagsForTag struct {
+	Name  string `structtag:"$name"`
+	Value int    `structtag:"value"`
+}
+
+func testTagsForGeneric[S any]() ([]spectagular.FieldTag[testTagsForTag], error) {
+	var sample S
+	return spectagular.TagsFor[testTagsForTag]("test", sample)
+}
+
+func TestTagsFor(t *testing.T) {
+	type TestTagsForStruct struct {
+		Field int `test:"field,value=1"`
+	}
+	tags, err := testTagsForGeneric[TestTagsForStruct]()
+	if err != nil {
+		t.Fatal("TestTagsFor: failed generic tags validation", err.Error())
+	}
+	assertEqual(t, len(tags), 1, "TestTagsFor: wrong number of tags:")
+	assertEqual(t, tags[0].Value.Name, "field", "TestTagsFor: wrong parsed name:")
+	assertEqual(t, tags[0].Value.Value, 1, "TestTagsFor: wrong parsed value:")
+	tags, err = testTagsForGeneric[*TestTagsForStruct]()
+	if err != nil {
+		t.Fatal("TestTagsFor: failed generic pointer tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Value, 1, "TestTagsFor: wrong parsed pointer value:")
+	if _, err := spectagular.TagsFor[testTagsForTag]("test", nil); err == nil {
+		t.Error("TestTagsFor: failed nil sample validation")
+	}
+}
+
+func TestNegatedBools(t *testing.T) {
+	type TestNegatedTag struct {
+		Name      string `structtag:"$name"`
+		OmitEmpty bool   `structtag:"omitempty"`
+		Inline    *bool  `structtag:"inline"`
+	}
+	type TestNegatedStruct struct {
+		Flag    int `test:"flag,omitempty,inline"`
+		Negated int `test:"negated,!omitempty,!inline"`
+		Last    int `test:"last,omitempty,!omitempty"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestNegatedTag]("test", reflect.TypeOf(TestNegatedStruct{}))
+	if err != nil {
+		t.Fatal("TestNegatedBools: failed negated tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.OmitEmpty, true, "TestNegatedBools: wrong flag value:")
+	assertEqual(t, *tags[0].Value.Inline, true, "TestNegatedBools: wrong pointer flag value:")
+	assertEqual(t, tags[1].Value.Name, "negated", "TestNegatedBools: wrong name:")
+	assertEqual(t, tags[1].Value.OmitEmpty, false, "TestNegatedBools: wrong negated value:")
+	if tags[1].Value.Inline == nil {
+		t.Fatal("TestNegatedBools: negated pointer flag was not set")
+	}
+	assertEqual(t, *tags[1].Value.Inline, false, "TestNegatedBools: wrong negated pointer value:")
+	assertEqual(t, tags[2].Value.OmitEmpty, false, "TestNegatedBools: wrong last value:")
+}
+
+func TestJSONSchema(t *testing.T) {
+	type TestSchemaTag struct {
+		Name     string `structtag:"$name"`
+		Required bool   `structtag:"required"`
+	}
+	type TestSchemaStruct struct {
+		ID      int               `test:"id,required"`
+		Name    *string           `test:"name,required"`
+		Score   float64           `test:"score"`
+		Tags    []string          `test:"tags"`
+		Labels  map[string]string `test:"labels"`
+		Created time.Time         `test:"created"`
+		Active  bool
+		Ignored string `test:"-"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestSchemaTag]("test")
+	schema, err := cache.JSONSchema(reflect.TypeOf(TestSchemaStruct{}))
+	if err != nil {
+		t.Fatal("TestJSONSchema: failed schema tags validation", err.Error())
+	}
+	expected := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id":      map[string]any{"type": "integer"},
+			"name":    map[string]any{"type": "string"},
+			"score":   map[string]any{"type": "number"},
+			"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"labels":  map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"created": map[string]any{"type": "string", "format": "date-time"},
+			"Active":  map[string]any{"type": "boolean"},
+		},
+		"required": []string{"id", "name"},
+	}
+	if !reflect.DeepEqual(schema, expected) {
+		t.Errorf("TestJSONSchema: wrong schema: %v != %v", schema, expected)
+	}
+}
+
+func TestCaseInsensitiveKeys(t *testing.T) {
+	type TestCaseTag struct {
+		Name      string `structtag:"$name"`
+		OmitEmpty bool   `structtag:"omitEmpty"`
+		MaxSize   int    `structtag:"maxsize"`
+	}
+	type TestCaseStruct struct {
+		Lower   int `test:"lower,omitempty,maxsize=1"`
+		Camel   int `test:"camel,omitEmpty,maxSize=2"`
+		Upper   int `test:"upper,OmitEmpty,MAXSIZE=3"`
+		Negated int `test:"negated,!OMITEMPTY"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestCaseTag]("test", spectagular.WithCaseInsensitiveKeys())
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestCaseStruct{}))
+	if err != nil {
+		t.Fatal("TestCaseInsensitiveKeys: failed case insensitive tags validation", err.Error())
+	}
+	for i, expected := range []string{"lower", "camel", "upper"} {
+		assertEqual(t, tags[i].Value.Name, expected, "TestCaseInsensitiveKeys: wrong name:")
+		assertEqual(t, tags[i].Value.OmitEmpty, true, "TestCaseInsensitiveKeys: wrong flag value:")
+		assertEqual(t, tags[i].Value.MaxSize, i+1, "TestCaseInsensitiveKeys: wrong parsed value:")
+	}
+	assertEqual(t, tags[3].Value.OmitEmpty, false, "TestCaseInsensitiveKeys: wrong negated value:")
+	if _, ok := cache.Lookup("OMITEMPTY"); !ok {
+		t.Error("TestCaseInsensitiveKeys: missing option lookup")
+	}
+	if err := cache.SetRequired("MaxSize", true); err != nil {
+		t.Fatal("TestCaseInsensitiveKeys: failed to set required option", err.Error())
+	}
+	type TestCaseMissing struct {
+		Field int `test:"field,omitempty"`
+	}
+	if _, err := cache.GetOrAdd(reflect.TypeOf(TestCaseMissing{})); !errors.Is(err, spectagular.ErrMissingRequired) {
+		t.Error("TestCaseInsensitiveKeys: expected ErrMissingRequired", err)
+	}
+	tags, err = spectagular.ParseTagsForType[TestCaseTag]("test", reflect.TypeOf(TestCaseStruct{}))
+	if err != nil {
+		t.Fatal("TestCaseInsensitiveKeys: failed case sensitive tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.OmitEmpty, false, "TestCaseInsensitiveKeys: wrong case sensitive value:")
+	assertEqual(t, tags[1].Value.OmitEmpty, true, "TestCaseInsensitiveKeys: wrong case sensitive value:")
+	assertEqual(t, tags[2].Value.MaxSize, 0, "TestCaseInsensitiveKeys: wrong case sensitive value:")
+}
+
+func TestURLDecode(t *testing.T) {
+	type TestURLTag struct {
+		Query   []string       `structtag:"q"`
+		Path    string         `structtag:"path"`
+		Weights map[string]int `structtag:"weights"`
+		Plain   []string       `structtag:"plain"`
+		String  string         `structtag:"s"`
+	}
+	type TestURLStruct struct {
+		Field int `test:"q=%5Ba%2Cb%5D,path=a%2Fb%20c,weights=%7Bx%3D1%2Cy%3D2%7D,plain=[c,d],s='[x]'"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestURLTag]("test", spectagular.WithURLDecode())
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestURLStruct{}))
+	if err != nil {
+		t.Fatal("TestURLDecode: failed url decoded tags validation", err.Error())
+	}
+	assertEqual(t, strings.Join(tags[0].Value.Query, "|"), "a|b", "TestURLDecode: wrong decoded list:")
+	assertEqual(t, tags[0].Value.Path, "a/b c", "TestURLDecode: wrong decoded value:")
+	assertEqual(t, tags[0].Value.Weights["y"], 2, "TestURLDecode: wrong decoded map:")
+	assertEqual(t, strings.Join(tags[0].Value.Plain, "|"), "c|d", "TestURLDecode: wrong plain list:")
+	assertEqual(t, tags[0].Value.String, "[x]", "TestURLDecode: brackets removed from string:")
+}
+
+func TestAddWithOptions(t *testing.T) {
+	type TestLayeredTag struct {
+		Name  string `structtag:"$name"`
+		Value int    `structtag:"value"`
+	}
+	type TestStrictStruct struct {
+		Field int `test:"field,unknown,value=1"`
+	}
+	type TestLenientStruct struct {
+		Field int `test:"field,unknown,value=2"`
+	}
+	type TestTrimmedStruct struct {
+		Field int `test:"field,value= 3"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestLayeredTag]("test", spectagular.WithTrimSpace())
+	err := cache.AddWithOptions(reflect.TypeOf(TestStrictStruct{}), spectagular.WithStrictBareValues())
+	if err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Error("TestAddWithOptions: failed strict bare value validation", err)
+	}
+	if _, ok := cache.Get(reflect.TypeOf(TestStrictStruct{})); ok {
+		t.Error("TestAddWithOptions: cached type that failed validation")
+	}
+	if err := cache.Add(reflect.TypeOf(TestLenientStruct{})); err != nil {
+		t.Fatal("TestAddWithOptions: failed lenient tags validation", err.Error())
+	}
+	if err := cache.AddWithOptions(reflect.TypeOf(TestTrimmedStruct{}), spectagular.WithStrictBareValues()); err != nil {
+		t.Fatal("TestAddWithOptions: failed layered tags validation", err.Error())
+	}
+	lenient, _ := cache.Get(reflect.TypeOf(TestLenientStruct{}))
+	assertEqual(t, lenient[0].Value.Value, 2, "TestAddWithOptions: wrong lenient value:")
+	// options the cache was created with still apply under the layered options
+	trimmed, _ := cache.Get(reflect.TypeOf(TestTrimmedStruct{}))
+	assertEqual(t, trimmed[0].Value.Value, 3, "TestAddWithOptions: wrong layered value:")
+}
+
+func TestAddWithOptionsDefinition(t *testing.T) {
+	type TestLayeredTag struct {
+		Name      string   `structtag:"$name"`
+		OmitEmpty bool     `structtag:"omitEmpty"`
+		List      []string `structtag:"list"`
+		Ratio     float64  `structtag:"ratio"`
+		Label     string   `structtag:"label"`
+	}
+	type TestHookStruct struct {
+		Field string `test:"field,label=ABC"`
+	}
+	type TestSeparatorStruct struct {
+		Field string `test:"field;list=[a;b]"`
+	}
+	type TestCaseStruct struct {
+		Field string `test:"field,OMITEMPTY"`
+	}
+	type TestCommaStruct struct {
+		Field string `test:"field,ratio='0,5'"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestLayeredTag]("test")
+	hook := func(field reflect.StructField, v reflect.Value) (reflect.Value, error) {
+		if v.Kind() == reflect.String {
+			return reflect.ValueOf(strings.ToLower(v.String())), nil
+		}
+		return v, nil
+	}
+	if err := cache.AddWithOptions(reflect.TypeOf(TestHookStruct{}), spectagular.WithConversionHook(hook)); err != nil {
+		t.Fatal("TestAddWithOptionsDefinition: failed conversion hook tags validation", err.Error())
+	}
+	tags, _ := cache.Get(reflect.TypeOf(TestHookStruct{}))
+	assertEqual(t, tags[0].Value.Label, "abc", "TestAddWithOptionsDefinition: conversion hook not called:")
+	if err := cache.AddWithOptions(reflect.TypeOf(TestSeparatorStruct{}), spectagular.WithSeparator(';')); err != nil {
+		t.Fatal("TestAddWithOptionsDefinition: failed separator tags validation", err.Error())
+	}
+	tags, _ = cache.Get(reflect.TypeOf(TestSeparatorStruct{}))
+	assertEqual(t, strings.Join(tags[0].Value.List, "|"), "a|b", "TestAddWithOptionsDefinition: wrong separated list:")
+	if err := cache.AddWithOptions(reflect.TypeOf(TestCaseStruct{}), spectagular.WithCaseInsensitiveKeys()); err != nil {
+		t.Fatal("TestAddWithOptionsDefinition: failed case insensitive tags validation", err.Error())
+	}
+	tags, _ = cache.Get(reflect.TypeOf(TestCaseStruct{}))
+	assertEqual(t, tags[0].Value.OmitEmpty, true, "TestAddWithOptionsDefinition: case insensitive key not matched:")
+	if err := cache.AddWithOptions(reflect.TypeOf(TestCommaStruct{}), spectagular.WithDecimalComma()); err != nil {
+		t.Fatal("TestAddWithOptionsDefinition: failed decimal comma tags validation", err.Error())
+	}
+	tags, _ = cache.Get(reflect.TypeOf(TestCommaStruct{}))
+	assertEqual(t, tags[0].Value.Ratio, 0.5, "TestAddWithOptionsDefinition: wrong decimal comma value:")
+	// the options of the cache itself are not changed
+	if err := cache.Add(reflect.TypeOf(TestCaseStruct{})); err != nil {
+		t.Fatal("TestAddWithOptionsDefinition: failed tags validation", err.Error())
+	}
+	tags, _ = cache.Get(reflect.TypeOf(TestCaseStruct{}))
+	assertEqual(t, tags[0].Value.OmitEmpty, false, "TestAddWithOptionsDefinition: layered option changed the cache:")
+}
+
+func TestAddWithOptionsRequired(t *testing.T) {
+	type TestLayeredTag struct {
+		Name  string `structtag:"$name"`
+		Value int    `structtag:"value"`
+	}
+	type TestMissingStruct struct {
+		Field int `test:"field"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestLayeredTag]("test")
+	if err := cache.SetRequired("value", true); err != nil {
+		t.Fatal("TestAddWithOptionsRequired: failed to set required", err.Error())
+	}
+	err := cache.AddWithOptions(reflect.TypeOf(TestMissingStruct{}), spectagular.WithCaseInsensitiveKeys())
+	if !errors.Is(err, spectagular.ErrMissingRequired) {
+		t.Error("TestAddWithOptionsRequired: required option not kept under layered options", err)
+	}
+}
+
+func TestAddWithOptionsRecursive(t *testing.T) {
+	type TestLayeredTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestRecursiveLeaf struct {
+		Value string `test:"value"`
+	}
+	type TestRecursiveRoot struct {
+		Leaf TestRecursiveLeaf `test:"leaf"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestLayeredTag]("test")
+	if err := cache.Add(reflect.TypeOf(TestRecursiveLeaf{})); err != nil {
+		t.Fatal("TestAddWithOptionsRecursive: failed leaf tags validation", err.Error())
+	}
+	cached, _ := cache.Get(reflect.TypeOf(TestRecursiveLeaf{}))
+	rename := func(name string) string { return "renamed" }
+	err := cache.AddWithOptions(reflect.TypeOf(TestRecursiveRoot{}), spectagular.WithRecursive(), spectagular.WithNameTransform(rename))
+	if err != nil {
+		t.Fatal("TestAddWithOptionsRecursive: failed recursive tags validation", err.Error())
+	}
+	assertEqual(t, cache.Len(), 2, "TestAddWithOptionsRecursive: wrong number of cached types:")
+	leaf, _ := cache.Get(reflect.TypeOf(TestRecursiveLeaf{}))
+	if &leaf[0] != &cached[0] {
+		t.Error("TestAddWithOptionsRecursive: cached nested type was parsed again")
+	}
+}
+
+func TestConversionHook(t *testing.T) {
+	type TestHookTag struct {
+		Host string `structtag:"host,required"`
+		Port int    `structtag:"port"`
+	}
+	type TestHookStruct struct {
+		Field string `test:"host=Example.COM,port=8080"`
+	}
+	type TestRejectedStruct struct {
+		Field string `test:"host=-,port=80"`
+	}
+	hook := func(field reflect.StructField, v reflect.Value) (reflect.Value, error) {
+		if v.Kind() != reflect.String {
+			return v, nil
+		}
+		if v.String() == "-" {
+			return v, errors.New("invalid host")
+		}
+		return reflect.ValueOf(strings.ToLower(v.String())), nil
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestHookTag]("test", spectagular.WithConversionHook(hook))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestHookStruct{}))
+	if err != nil {
+		t.Fatal("TestConversionHook: failed tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Host, "example.com", "TestConversionHook: wrong host:")
+	assertEqual(t, tags[0].Value.Port, 8080, "TestConversionHook: wrong port:")
+	var conversionErr *spectagular.ConversionError
+	if err := cache.Add(reflect.TypeOf(TestRejectedStruct{})); !errors.As(err, &conversionErr) || !strings.Contains(err.Error(), "invalid host") {
+		t.Error("TestConversionHook: hook error not returned for required option", err)
+	}
+}
+
+func TestNameTransform(t *testing.T) {
+	type TestTransformTag struct {
+		Name      string `structtag:"$name"`
+		OmitEmpty bool   `structtag:"omitempty"`
+	}
+	type TestTransformStruct struct {
+		UserID    string `test:",omitempty"`
+		CreatedAt string `test:"created"`
+	}
+	snakeCase := func(name string) string {
+		var sb strings.Builder
+		for i, r := range name {
+			if i > 0 && r >= 'A' && r <= 'Z' && (name[i-1] < 'A' || name[i-1] > 'Z') {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		}
+		return strings.ToLower(sb.String())
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestTransformTag]("test", spectagular.WithNameTransform(snakeCase))
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestTransformStruct{}))
+	if err != nil {
+		t.Fatal("TestNameTransform: failed tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "user_id", "TestNameTransform: wrong transformed name:")
+	assertEqual(t, tags[0].Value.OmitEmpty, true, "TestNameTransform: wrong omitempty:")
+	assertEqual(t, tags[1].Value.Name, "created", "TestNameTransform: wrong given name:")
+}
+
+func TestParseTagsForValue(t *testing.T) {
+	type TestValueStruct struct {
+		Field int `test:"field,value=1"`
+	}
+	tags, err := spectagular.ParseTagsForValue[testTagsForTag]("test", TestValueStruct{})
+	if err != nil {
+		t.Fatal("TestParseTagsForValue: failed struct value tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "field", "TestParseTagsForValue: wrong parsed name:")
+	assertEqual(t, tags[0].Value.Value, 1, "TestParseTagsForValue: wrong parsed value:")
+	tags, err = spectagular.ParseTagsForValue[testTagsForTag]("test", &TestValueStruct{})
+	if err != nil {
+		t.Fatal("TestParseTagsForValue: failed pointer tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Value, 1, "TestParseTagsForValue: wrong parsed pointer value:")
+	cache, _ := spectagular.NewFieldTagCache[testTagsForTag]("test")
+	if _, err := cache.GetOrAddValue(&TestValueStruct{}); err != nil {
+		t.Fatal("TestParseTagsForValue: failed pointer GetOrAddValue", err.Error())
+	}
+	if _, ok := cache.Get(reflect.TypeOf(TestValueStruct{})); !ok {
+		t.Error("TestParseTagsForValue: pointer value was not cached as its struct type")
+	}
+	if _, err := cache.GetOrAddValue(nil); err == nil {
+		t.Error("TestParseTagsForValue: failed nil value validation")
+	}
+}
+
+func TestIR(t *testing.T) {
+	type TestIRTag struct {
+		Name      string        `structtag:"$name"`
+		OmitEmpty bool          `structtag:"omitempty"`
+		Values    []string      `structtag:"values"`
+		Timeout   time.Duration `structtag:"timeout"`
+		Max       *int          `structtag:"max"`
+	}
+	type TestIRUser struct {
+		ID    int    `test:"id,omitempty,timeout=5s"`
+		Roles string `test:"roles,values=[admin,user]"`
+	}
+	type TestIRGroup struct {
+		Name string `test:"name,max=10"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestIRTag]("test")
+	for _, rType := range []reflect.Type{reflect.TypeOf(TestIRUser{}), reflect.TypeOf(TestIRGroup{})} {
+		if err := cache.Add(rType); err != nil {
+			t.Fatal("TestIR: failed tags validation", err.Error())
+		}
+	}
+	data, err := cache.MarshalIR()
+	if err != nil {
+		t.Fatal("TestIR: failed to marshal IR", err.Error())
+	}
+	again, _ := cache.MarshalIR()
+	assertEqual(t, string(again), string(data), "TestIR: unstable IR encoding:")
+	ir, err := spectagular.UnmarshalIR(data)
+	if err != nil {
+		t.Fatal("TestIR: failed to unmarshal IR", err.Error())
+	}
+	assertEqual(t, ir.Tag, "test", "TestIR: wrong tag:")
+	assertEqual(t, strings.Join(ir.Options, ","), "$name,omitempty,values,timeout,max", "TestIR: wrong options:")
+	user, ok := ir.Lookup("github.com/matt1484/spectagular_test.TestIRUser")
+	if !ok {
+		t.Fatal("TestIR: missing user type")
+	}
+	id, _ := user.Field("ID")
+	assertEqual(t, id.Type, "int", "TestIR: wrong field type:")
+	assertEqual(t, id.Index[0], 0, "TestIR: wrong field index:")
+	assertEqual(t, id.Values["$name"], "id", "TestIR: wrong name value:")
+	assertEqual(t, id.Values["omitempty"], "true", "TestIR: wrong bool value:")
+	assertEqual(t, id.Values["timeout"], "5s", "TestIR: wrong duration value:")
+	if _, ok := id.Values["max"]; ok {
+		t.Error("TestIR: nil option was not left out")
+	}
+	roles, _ := user.Field("Roles")
+	assertEqual(t, roles.Index[0], 1, "TestIR: wrong field index:")
+	assertEqual(t, roles.Values["values"], "[admin,user]", "TestIR: wrong list value:")
+	assertEqual(t, roles.Values["omitempty"], "false", "TestIR: wrong bool value:")
+	group, ok := ir.Lookup("github.com/matt1484/spectagular_test.TestIRGroup")
+	if !ok {
+		t.Fatal("TestIR: missing group type")
+	}
+	name, _ := group.Field("Name")
+	assertEqual(t, name.Values["max"], "10", "TestIR: wrong pointer value:")
+	if _, ok := ir.Lookup("TestIRMissing"); ok {
+		t.Error("TestIR: found missing type")
+	}
+	if _, err := spectagular.UnmarshalIR([]byte("{")); err == nil {
+		t.Error("TestIR: failed invalid IR validation")
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	type TestRecursiveTag struct {
+		Name string `structtag:"$name"`
+	}
+	type TestRecursiveLeaf struct {
+		Value   string    `test:"value"`
+		Created time.Time `test:"created"`
+	}
+	type TestRecursiveMiddle struct {
+		Leaves []TestRecursiveLeaf `test:"leaves"`
+	}
+	type TestRecursiveRoot struct {
+		Middle *TestRecursiveMiddle `test:"middle"`
+		Other  TestRecursiveMiddle  `test:"other"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestRecursiveTag]("test", spectagular.WithRecursive())
+	if err := cache.Add(reflect.TypeOf(TestRecursiveRoot{})); err != nil {
+		t.Fatal("TestRecursive: failed recursive tags validation", err.Error())
+	}
+	assertEqual(t, cache.Len(), 3, "TestRecursive: wrong number of cached types:")
+	middle, ok := cache.Get(reflect.TypeOf(TestRecursiveMiddle{}))
+	if !ok {
+		t.Fatal("TestRecursive: nested type was not cached")
+	}
+	assertEqual(t, middle[0].Value.Name, "leaves", "TestRecursive: wrong nested name:")
+	leaf, ok := cache.Get(reflect.TypeOf(TestRecursiveLeaf{}))
+	if !ok {
+		t.Fatal("TestRecursive: second level nested type was not cached")
+	}
+	assertEqual(t, leaf[0].Value.Name, "value", "TestRecursive: wrong second level nested name:")
+	if _, ok := cache.Get(reflect.TypeOf(time.Time{})); ok {
+		t.Error("TestRecursive: cached struct without exported fields")
+	}
+	type TestRecursiveInvalidLeaf struct {
+		Value string `test:"value,unknown"`
+	}
+	type TestRecursiveInvalidRoot struct {
+		Leaf TestRecursiveInvalidLeaf `test:"leaf"`
+	}
+	strict, _ := spectagular.NewFieldTagCacheWithOptions[TestRecursiveTag]("test", spectagular.WithRecursive(), spectagular.WithStrictBareValues())
+	if err := strict.Add(reflect.TypeOf(TestRecursiveInvalidRoot{})); err == nil || !strings.Contains(err.Error(), "nested type") {
+		t.Error("TestRecursive: failed nested type validation", err)
+	}
+	assertEqual(t, strict.Len(), 0, "TestRecursive: cached type with invalid nested type:")
+}
+
+func TestSkipTag(t *testing.T) {
+	type TestSkipTag struct {
+		Name  string `structtag:"$name"`
+		Value int    `structtag:"value,required"`
+	}
+	type TestSkipStruct struct {
+		First   int `test:"first,value=1"`
+		Skipped int `test:"-"`
+		Dash    int `test:"-,value=2"`
+		Other   int `other:"-"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestSkipTag]("test")
+	_, err := cache.GetOrAdd(reflect.TypeOf(TestSkipStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "Other") {
+		t.Error("TestSkipTag: field skipped by a different tag name", err)
+	}
+	type TestSkipValidStruct struct {
+		First   int `test:"first,value=1"`
+		Skipped int `test:"-"`
+		Dash    int `test:"-,value=2"`
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestSkipValidStruct{}))
+	if err != nil {
+		t.Fatal("TestSkipTag: failed skipped field validation", err.Error())
+	}
+	assertEqual(t, len(tags), 2, "TestSkipTag: wrong number of tags:")
+	assertEqual(t, tags[0].FieldName, "First", "TestSkipTag: wrong field:")
+	assertEqual(t, tags[1].FieldName, "Dash", "TestSkipTag: wrong field:")
+	assertEqual(t, tags[1].Value.Name, "-", "TestSkipTag: wrong name:")
+	if _, ok := cache.GetField(reflect.TypeOf(TestSkipValidStruct{}), "Skipped"); ok {
+		t.Error("TestSkipTag: found skipped field")
+	}
+}
+
+func TestUnexported(t *testing.T) {
+	type TestUnexportedTag struct {
+		Name  string `structtag:"$name"`
+		Value int    `structtag:"value"`
+	}
+	type TestUnexportedStruct struct {
+		Public  int `test:"public"`
+		private int `test:"private,value=2"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestUnexportedTag]("test")
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestUnexportedStruct{}))
+	if err != nil {
+		t.Fatal("TestUnexported: failed tags validation", err.Error())
+	}
+	assertEqual(t, len(tags), 1, "TestUnexported: wrong number of tags without WithUnexported:")
+	cache, _ = spectagular.NewFieldTagCacheWithOptions[TestUnexportedTag]("test", spectagular.WithUnexported())
+	tags, err = cache.GetOrAdd(reflect.TypeOf(TestUnexportedStruct{}))
+	if err != nil {
+		t.Fatal("TestUnexported: failed unexported tags validation", err.Error())
+	}
+	assertEqual(t, len(tags), 2, "TestUnexported: wrong number of tags:")
+	assertEqual(t, tags[1].FieldName, "private", "TestUnexported: wrong field name:")
+	assertEqual(t, tags[1].FieldIndex, 1, "TestUnexported: wrong field index:")
+	assertEqual(t, tags[1].Value.Name, "private", "TestUnexported: wrong name:")
+	assertEqual(t, tags[1].Value.Value, 2, "TestUnexported: wrong value:")
+}
+
+func TestMultiTagNames(t *testing.T) {
+	type TestMultiTag struct {
+		Name      string `structtag:"$name"`
+		OmitEmpty bool   `structtag:"omitempty"`
+		Required  bool   `structtag:"required"`
+		Max       int    `structtag:"max"`
+	}
+	type TestMultiStruct struct {
+		Email string `json:"email,omitempty" validate:"required,max=64"`
+		Name  string `json:"name,max=10" validate:"max=20"`
+		ID    int    `validate:"required"`
+		Plain int
+	}
+	cache, err := spectagular.NewFieldTagCacheMulti[TestMultiTag]("json", "validate")
+	if err != nil {
+		t.Fatal("TestMultiTagNames: failed multi validation", err.Error())
+	}
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestMultiStruct{}))
+	if err != nil {
+		t.Fatal("TestMultiTagNames: failed multi tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "email", "TestMultiTagNames: wrong name:")
+	assertEqual(t, tags[0].Value.OmitEmpty, true, "TestMultiTagNames: wrong omitempty:")
+	assertEqual(t, tags[0].Value.Required, true, "TestMultiTagNames: wrong required:")
+	assertEqual(t, tags[0].Value.Max, 64, "TestMultiTagNames: wrong max:")
+	assertEqual(t, tags[0].Raw, "email,omitempty", "TestMultiTagNames: wrong raw tag:")
+	assertEqual(t, tags[1].Value.Max, 20, "TestMultiTagNames: later tag name did not override:")
+	assertEqual(t, tags[2].Value.Required, true, "TestMultiTagNames: wrong required:")
+	assertEqual(t, tags[3].Value.Required, false, "TestMultiTagNames: wrong required:")
+	type TestMultiBareStruct struct {
+		Named     string `json:"named" validate:"oops,max=1"`
+		Secondary string `validate:"oops,max=1"`
+	}
+	tags, err = cache.GetOrAdd(reflect.TypeOf(TestMultiBareStruct{}))
+	if err != nil {
+		t.Fatal("TestMultiTagNames: failed bare value tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Name, "named", "TestMultiTagNames: bare value of later tag name overrode name:")
+	assertEqual(t, tags[1].Value.Name, "", "TestMultiTagNames: bare value of later tag name used as name:")
+	assertEqual(t, tags[1].Value.Max, 1, "TestMultiTagNames: wrong max after bare value:")
+	if _, err := spectagular.NewFieldTagCacheMulti[TestMultiTag](); err == nil {
+		t.Error("TestMultiTagNames: failed missing tag names validation")
+	}
+	if _, err := spectagular.NewFieldTagCacheMulti[TestMultiTag]("json", ""); err == nil {
+		t.Error("TestMultiTagNames: failed empty tag name validation")
+	}
+}
+
+func TestAliases(t *testing.T) {
+	type TestAliasTag struct {
+		Name       string `structtag:"$name"`
+		PrimaryKey bool   `structtag:"primaryKey" alias:"pk,primary"`
+		Column     string `structtag:"column" alias:"col"`
+	}
+	type TestAliasStruct struct {
+		Primary  int `test:"id,primaryKey,column=user_id"`
+		Alias    int `test:"id,pk,col=user_id"`
+		Other    int `test:"id,primary"`
+		Negated  int `test:"id,primaryKey,!pk"`
+		Unmarked int `test:"id"`
+	}
+	cache, err := spectagular.NewFieldTagCache[TestAliasTag]("test")
+	if err != nil {
+		t.Fatal("TestAliases: failed alias validation", err.Error())
+	}
+	opts, _ := cache.Lookup("primaryKey")
+	assertEqual(t, strings.Join(opts[0].Aliases, ","), "pk,primary", "TestAliases: wrong aliases:")
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestAliasStruct{}))
+	if err != nil {
+		t.Fatal("TestAliases: failed alias tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.PrimaryKey, true, "TestAliases: wrong primary key:")
+	assertEqual(t, tags[0].Value.Column, "user_id", "TestAliases: wrong column:")
+	assertEqual(t, tags[1].Value.PrimaryKey, true, "TestAliases: wrong aliased primary key:")
+	assertEqual(t, tags[1].Value.Column, "user_id", "TestAliases: wrong aliased column:")
+	assertEqual(t, tags[2].Value.PrimaryKey, true, "TestAliases: wrong aliased primary key:")
+	assertEqual(t, tags[3].Value.PrimaryKey, false, "TestAliases: wrong negated alias:")
+	assertEqual(t, tags[4].Value.PrimaryKey, false, "TestAliases: wrong primary key:")
+	type TestAliasNameConflict struct {
+		Name   string `structtag:"$name"`
+		Column string `structtag:"column" alias:"name"`
+		Other  string `structtag:"name"`
+	}
+	if _, err := spectagular.NewFieldTagCache[TestAliasNameConflict]("test"); !errors.Is(err, spectagular.ErrDuplicateTagName) {
+		t.Error("TestAliases: failed alias and name conflict validation", err)
+	}
+	type TestAliasConflict struct {
+		Column string `structtag:"column" alias:"col"`
+		Other  string `structtag:"other" alias:"col"`
+	}
+	if _, err := spectagular.NewFieldTagCache[TestAliasConflict]("test"); !errors.Is(err, spectagular.ErrDuplicateTagName) {
+		t.Error("TestAliases: failed conflicting alias validation", err)
+	}
+}
+
+func TestStrictKeys(t *testing.T) {
+	type TestStrictKeysTag struct {
+		Name     string `structtag:"$name"`
+		Required bool   `structtag:"required"`
+		Max      int    `structtag:"max"`
+	}
+	type TestStrictKeysStruct struct {
+		Field int `test:"field,requried=true,max=5"`
+	}
+	lenient, _ := spectagular.NewFieldTagCache[TestStrictKeysTag]("test")
+	tags, err := lenient.GetOrAdd(reflect.TypeOf(TestStrictKeysStruct{}))
+	if err != nil {
+		t.Fatal("TestStrictKeys: failed lenient tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Required, false, "TestStrictKeys: wrong required:")
+	assertEqual(t, tags[0].Value.Max, 5, "TestStrictKeys: wrong max:")
+	strict, _ := spectagular.NewFieldTagCacheWithOptions[TestStrictKeysTag]("test", spectagular.WithStrictKeys())
+	_, err = strict.GetOrAdd(reflect.TypeOf(TestStrictKeysStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "'requried'") || !strings.Contains(err.Error(), "Field") {
+		t.Error("TestStrictKeys: failed unknown key validation", err)
+	}
+	type TestStrictKeysValid struct {
+		Field int `test:"field,required=true,max=5"`
+	}
+	if err := strict.Add(reflect.TypeOf(TestStrictKeysValid{})); err != nil {
+		t.Error("TestStrictKeys: failed strict tags validation", err.Error())
+	}
+}
+
+func TestRepeatedKeys(t *testing.T) {
+	type TestRepeatedTag struct {
+		Name string   `structtag:"$name"`
+		With []string `structtag:"with"`
+		Max  int      `structtag:"max"`
+	}
+	type TestRepeatedStruct struct {
+		Repeated int `test:"field,with=a,with=b,max=1,with=c,max=2"`
+		Lists    int `test:"field,with=[a,b],with=c"`
+		Single   int `test:"field,with=a"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestRepeatedTag]("test", reflect.TypeOf(TestRepeatedStruct{}))
+	if err != nil {
+		t.Fatal("TestRepeatedKeys: failed repeated tags validation", err.Error())
+	}
+	assertEqual(t, strings.Join(tags[0].Value.With, ","), "a,b,c", "TestRepeatedKeys: wrong repeated values:")
+	assertEqual(t, tags[0].Value.Max, 2, "TestRepeatedKeys: wrong last value:")
+	assertEqual(t, strings.Join(tags[1].Value.With, ","), "a,b,c", "TestRepeatedKeys: wrong repeated lists:")
+	assertEqual(t, strings.Join(tags[2].Value.With, ","), "a", "TestRepeatedKeys: wrong single value:")
+}
+
+func TestRepeatedKeyLengths(t *testing.T) {
+	type TestRepeatedTag struct {
+		Name string   `structtag:"$name"`
+		With []string `structtag:"with,minlen=2,maxlen=2"`
+	}
+	type TestRepeatedStruct struct {
+		Field int `test:"field,with=a,with=b"`
+	}
+	type TestTooLongStruct struct {
+		Field int `test:"field,with=a,with=b,with=c"`
+	}
+	cache, _ := spectagular.NewFieldTagCache[TestRepeatedTag]("test")
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestRepeatedStruct{}))
+	if err != nil {
+		t.Fatal("TestRepeatedKeyLengths: failed repeated tags validation", err.Error())
+	}
+	assertEqual(t, strings.Join(tags[0].Value.With, ","), "a,b", "TestRepeatedKeyLengths: wrong repeated values:")
+	if err := cache.Add(reflect.TypeOf(TestTooLongStruct{})); err == nil || !strings.Contains(err.Error(), "maxlen") {
+		t.Error("TestRepeatedKeyLengths: failed maxlen validation of repeated values", err)
+	}
+}
+
+func TestRepeatedKeysMultiTagNames(t *testing.T) {
+	type TestRepeatedTag struct {
+		Name string   `structtag:"$name"`
+		With []string `structtag:"with"`
+	}
+	type TestRepeatedStruct struct {
+		Field int `json:"field,with=x" validate:"with=y,with=z"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheMulti[TestRepeatedTag]("json", "validate")
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestRepeatedStruct{}))
+	if err != nil {
+		t.Fatal("TestRepeatedKeysMultiTagNames: failed repeated tags validation", err.Error())
+	}
+	assertEqual(t, strings.Join(tags[0].Value.With, ","), "y,z", "TestRepeatedKeysMultiTagNames: later tag name did not override:")
+}
+
+func TestEscapedSeparators(t *testing.T) {
+	type TestEscapedTag struct {
+		Name  string            `structtag:"$name"`
+		Value string            `structtag:"s"`
+		Max   int               `structtag:"max"`
+		Pairs map[string]string `structtag:"pairs"`
+	}
+	type TestEscapedStruct struct {
+		Comma     int `test:"field,s=a\\,b,max=1"`
+		Backslash int `test:"field,s=a\\\\,max=2"`
+		Path      int `test:"field,s=C:\\dir\\d+"`
+		Quote     int `test:"field,s=it\\'s"`
+		Map       int `test:"field,pairs={k=a\\,b}"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestEscapedTag]("test", reflect.TypeOf(TestEscapedStruct{}))
+	if err != nil {
+		t.Fatal("TestEscapedSeparators: failed escaped tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.Value, "a,b", "TestEscapedSeparators: wrong escaped comma:")
+	assertEqual(t, tags[0].Value.Max, 1, "TestEscapedSeparators: wrong value after escaped comma:")
+	assertEqual(t, tags[1].Value.Value, `a\`, "TestEscapedSeparators: wrong escaped backslash:")
+	assertEqual(t, tags[1].Value.Max, 2, "TestEscapedSeparators: wrong value after escaped backslash:")
+	assertEqual(t, tags[2].Value.Value, `C:\dir\d+`, "TestEscapedSeparators: wrong unescaped backslashes:")
+	assertEqual(t, tags[3].Value.Value, "it's", "TestEscapedSeparators: wrong escaped quote:")
+	assertEqual(t, tags[4].Value.Pairs["k"], "a,b", "TestEscapedSeparators: wrong escaped map value:")
+}
+
+func TestEscapedSliceSeparators(t *testing.T) {
+	type TestEscapedTag struct {
+		Name string   `structtag:"$name"`
+		List []string `structtag:"l"`
+	}
+	type TestEscapedStruct struct {
+		Unbracketed int `test:"field,l=a\\,b"`
+		Bracketed   int `test:"field,l=[a\\,b,c]"`
+		Backslash   int `test:"field,l=a\\\\\\,b"`
+	}
+	tags, err := spectagular.ParseTagsForType[TestEscapedTag]("test", reflect.TypeOf(TestEscapedStruct{}))
+	if err != nil {
+		t.Fatal("TestEscapedSliceSeparators: failed escaped tags validation", err.Error())
+	}
+	assertEqual(t, strings.Join(tags[0].Value.List, "|"), "a,b", "TestEscapedSliceSeparators: wrong unbracketed list:")
+	assertEqual(t, strings.Join(tags[1].Value.List, "|"), "a,b|c", "TestEscapedSliceSeparators: wrong bracketed list:")
+	assertEqual(t, strings.Join(tags[2].Value.List, "|"), `a\,b`, "TestEscapedSliceSeparators: wrong escaped backslash list:")
+}
+
+func TestEscapedDecodeEscapes(t *testing.T) {
+	type TestEscapedTag struct {
+		Name   string   `structtag:"$name"`
+		String string   `structtag:"s"`
+		List   []string `structtag:"l"`
+	}
+	type TestEscapedStruct struct {
+		Backslash int `test:"field,s=a\\\\n"`
+		Newline   int `test:"field,s=a\\n"`
+		Comma     int `test:"field,s=a\\,b\\n"`
+		List      int `test:"field,l=[a\\\\n,b\\n]"`
+	}
+	cache, _ := spectagular.NewFieldTagCacheWithOptions[TestEscapedTag]("test", spectagular.WithDecodeEscapes())
+	tags, err := cache.GetOrAdd(reflect.TypeOf(TestEscapedStruct{}))
+	if err != nil {
+		t.Fatal("TestEscapedDecodeEscapes: failed escaped tags validation", err.Error())
+	}
+	assertEqual(t, tags[0].Value.String, `a\n`, "TestEscapedDecodeEscapes: escaped backslash decoded twice:")
+	assertEqual(t, tags[1].Value.String, "a\n", "TestEscapedDecodeEscapes: wrong decoded newline:")
+	assertEqual(t, tags[2].Value.String, "a,b\n", "TestEscapedDecodeEscapes: wrong escaped comma:")
+	assertEqual(t, strings.Join(tags[3].Value.List, "|"), "a\\n|b\n", "TestEscapedDecodeEscapes: wrong decoded list:")
+}
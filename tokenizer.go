@@ -0,0 +1,283 @@
+package spectagular
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	keyValueRegex       = regexp.MustCompile(`^(?:(\w+)=)?`)
+	untilNextQuoteRegex = regexp.MustCompile(`^([^']*)'`)
+	profileRegex        = regexp.MustCompile(`^ *(\w+):\{`)
+)
+
+// TagToken is a single option scanned from a struct tag. Key is empty for options that were
+// given without one (i.e. the $name option or boolean flags) and Value has any quotes or
+// brackets removed.
+type TagToken struct {
+	Key   string
+	Value string
+
+	// escaped is the value with its escapes kept if it was unquoted and had any, so that lists
+	// can be split by their resolver without splitting at escaped separators
+	escaped string
+	// bracket is the opening bracket of the value if it was bracketed, so that raw JSON can be
+	// given the value as written
+	bracket byte
+}
+
+// Tokenizer is an interface used to split a struct tag into the options it contains. The
+// resulting tokens are fed through the same resolvers regardless of how the tag was split.
+type Tokenizer interface {
+	Tokenize(tag string) ([]TagToken, error)
+}
+
+// DefaultTokenizer is the Tokenizer used when none is given. It splits tags of the form
+// `key=value,key='quoted value',key=[value,...]` where keys are optional. Values can also be
+// grouped with braces (i.e. `key={k=v,...}` for maps).
+type DefaultTokenizer struct {
+	// Separator is used in place of the comma between options (and list values) if it is set.
+	Separator rune
+}
+
+// Tokenize splits a comma (or Separator) delimited struct tag into its options.
+func (d DefaultTokenizer) Tokenize(tag string) ([]TagToken, error) {
+	tokens, _, err := splitTail(tag, -1, d.separator(), false)
+	return tokens, err
+}
+
+// separator returns the separator between options as a string.
+func (d DefaultTokenizer) separator() string {
+	if d.Separator == 0 {
+		return ","
+	}
+	return string(d.Separator)
+}
+
+// splitTail splits the first n options of a tag (or all of them if n is negative) that are
+// delimited by sep and returns them along with the rest of the tag as is. If keepBackslashes is
+// set, escaped backslashes in unquoted values are kept as is (i.e. to be decoded by
+// WithDecodeEscapes).
+func splitTail(tag string, n int, sep string, keepBackslashes bool) ([]TagToken, string, error) {
+	tokens := make([]TagToken, 0)
+	var err error
+	for tag != EmptyTag && len(tokens) != n {
+		token := TagToken{}
+		kv := keyValueRegex.FindStringSubmatchIndex(tag)
+		if kv[3] > 0 {
+			token.Key = tag[kv[2]:kv[3]]
+		}
+		tag = tag[kv[1]:]
+		if tag != EmptyTag && (tag[0] == '[' || tag[0] == '{') {
+			token.bracket = tag[0]
+			tag, token.Value, err = getNextBracketValue(tag[1:], closingBracket(tag[0]))
+			tag = strings.TrimPrefix(tag, sep)
+		} else if tag != EmptyTag && tag[0] == '\'' {
+			tag, token.Value, err = getNextTagValue(tag, sep, keepBackslashes)
+			tag = strings.TrimPrefix(tag, sep)
+		} else {
+			var raw string
+			tag, token.Value, raw = getNextUnquotedValue(tag, sep, keepBackslashes)
+			if raw != token.Value {
+				token.escaped = raw
+			}
+		}
+		if err != nil {
+			return nil, EmptyTag, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, tag, nil
+}
+
+// getNextTagValue reads the next (possibly quoted) value from a tag and returns the rest of the tag
+// along with the value. Unquoted values end at the next sep that is not escaped.
+func getNextTagValue(tag string, sep string, keepBackslashes bool) (string, string, error) {
+	valueStr := ""
+	var kv []int
+	if tag != EmptyTag && tag[0] == '\'' {
+		tag = tag[1:]
+		for {
+			kv = untilNextQuoteRegex.FindStringSubmatchIndex(tag)
+			if kv == nil {
+				return "", "", errors.New("missing end quote on quoted string")
+			}
+			valueStr += tag[kv[2]:kv[3]]
+			if kv[3] > 0 && kv[3] > kv[2] && tag[kv[3]-1] == '\\' {
+				valueStr = valueStr[:len(valueStr)-1] + "'"
+				tag = tag[kv[1]:]
+			} else {
+				break
+			}
+		}
+		tag = tag[kv[1]:]
+	} else {
+		tag, valueStr, _ = getNextUnquotedValue(tag, sep, keepBackslashes)
+	}
+	return tag, valueStr, nil
+}
+
+// getNextUnquotedValue reads an unquoted value that ends at the next sep and returns the rest of
+// the tag along with the value and the value as it was written. A backslash escapes sep, a quote,
+// or another backslash (i.e. `a\,b` is read as `a,b`) while any other backslash is kept as is.
+// Escaped backslashes are kept as is if keepBackslashes is set.
+func getNextUnquotedValue(tag string, sep string, keepBackslashes bool) (string, string, string) {
+	var valueStr strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch rest := tag[i+1:]; {
+		case tag[i] == '\\' && strings.HasPrefix(rest, sep):
+			valueStr.WriteString(sep)
+			i += len(sep)
+		case tag[i] == '\\' && keepBackslashes && rest != EmptyTag && rest[0] == '\\':
+			valueStr.WriteString(tag[i : i+2])
+			i++
+		case tag[i] == '\\' && rest != EmptyTag && (rest[0] == '\\' || rest[0] == '\''):
+			valueStr.WriteByte(rest[0])
+			i++
+		case strings.HasPrefix(tag[i:], sep):
+			return tag[i+len(sep):], valueStr.String(), tag[:i]
+		default:
+			valueStr.WriteByte(tag[i])
+		}
+	}
+	return EmptyTag, valueStr.String(), tag
+}
+
+// closingBracket returns the bracket that closes an opening bracket or brace.
+func closingBracket(open byte) byte {
+	if open == '{' {
+		return '}'
+	}
+	return ']'
+}
+
+// getNextBracketValue reads a bracketed list (with the opening bracket already removed) and returns
+// the rest of the tag along with the contents of the list. Quoted values are kept as is so that any
+// closing brackets inside of them do not end the list.
+func getNextBracketValue(tag string, end byte) (string, string, error) {
+	var valueStr strings.Builder
+	quoted := false
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		switch {
+		case c == '\\' && i+1 < len(tag) && tag[i+1] == '\\':
+			// escaped backslashes are kept for the resolver so that they do not escape the end
+			valueStr.WriteString(tag[i : i+2])
+			i++
+		case c == '\\' && i+1 < len(tag) && tag[i+1] == end:
+			valueStr.WriteByte(end)
+			i++
+		case c == '\\' && i+1 < len(tag) && tag[i+1] == '\'':
+			valueStr.WriteString(tag[i : i+2])
+			i++
+		case c == '\'':
+			quoted = !quoted
+			valueStr.WriteByte(c)
+		case c == end && !quoted:
+			return tag[i+1:], valueStr.String(), nil
+		default:
+			valueStr.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("missing end bracket '%c' on bracketed list", end)
+}
+
+// AllTags returns every namespace in a struct field's tag mapped to its raw value (i.e.
+// `json:"id" db:"user_id"` becomes {"json": "id", "db": "user_id"}). Parsing follows the same
+// conventions as reflect.StructTag.Lookup and stops at the first malformed entry.
+func AllTags(sf reflect.StructField) map[string]string {
+	tags := make(map[string]string)
+	tag := string(sf.Tag)
+	for tag != EmptyTag {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == EmptyTag {
+			break
+		}
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		tags[name] = value
+		tag = tag[i+1:]
+	}
+	return tags
+}
+
+// SplitNamePath splits a dotted name (i.e. `db.host`) into the segments of the path it describes
+// so that flattened names can be mapped to nested values. Names are never split while parsing.
+func SplitNamePath(name string) []string {
+	if name == EmptyTag {
+		return []string{}
+	}
+	return strings.Split(name, ".")
+}
+
+// selectProfile returns the options of a profile from a tag of the form `name:{...};name:{...}`
+// (or an empty string if the profile is not in the tag). Braces inside of quotes are ignored.
+// Tags that are not of this form are returned as is.
+func selectProfile(tag string, profile string) (string, error) {
+	if !profileRegex.MatchString(tag) {
+		return tag, nil
+	}
+	selected := EmptyTag
+	for tag != EmptyTag {
+		match := profileRegex.FindStringSubmatchIndex(tag)
+		if match == nil {
+			return EmptyTag, errors.New("invalid profile group: " + tag)
+		}
+		name := tag[match[2]:match[3]]
+		tag = tag[match[1]:]
+		depth, quoted, end := 1, false, -1
+		for i := 0; i < len(tag) && end < 0; i++ {
+			switch c := tag[i]; {
+			case c == '\\':
+				i++
+			case c == '\'':
+				quoted = !quoted
+			case quoted:
+			case c == '{':
+				depth++
+			case c == '}':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end < 0 {
+			return EmptyTag, errors.New("missing end brace on profile: " + name)
+		}
+		if name == profile {
+			selected = tag[:end]
+		}
+		tag = strings.TrimPrefix(strings.TrimLeft(tag[end+1:], " "), ";")
+	}
+	return selected, nil
+}
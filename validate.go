@@ -0,0 +1,168 @@
+package spectagular
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// validateValue checks a resolved value against the constraints declared for the option.
+func (st StructTagOption) validateValue(v reflect.Value) error {
+	v = reflect.Indirect(v)
+	if number, ok := numericValue(v); ok {
+		if st.Min != nil && number < *st.Min {
+			return fmt.Errorf("value %v of option '%s' is less than min %v", number, st.Name, *st.Min)
+		}
+		if st.Max != nil && number > *st.Max {
+			return fmt.Errorf("value %v of option '%s' is greater than max %v", number, st.Name, *st.Max)
+		}
+	}
+	if len(st.AllowedValues) > 0 && !st.isAllowed(v) {
+		return fmt.Errorf("invalid value '%v' of option '%s', expected one of: %s", v.Interface(), st.Name, strings.Join(st.AllowedValues, ", "))
+	}
+	return nil
+}
+
+// validateLength checks the length of a slice option against its minlen and maxlen. It is checked
+// once every value of the option has been given since repeated keys append to slice options.
+func (st StructTagOption) validateLength(v reflect.Value) error {
+	v = reflect.Indirect(v)
+	if v.Kind() == reflect.Slice {
+		if st.MinLen > 0 && v.Len() < st.MinLen {
+			return fmt.Errorf("length %d of option '%s' is less than minlen %d", v.Len(), st.Name, st.MinLen)
+		}
+		if st.MaxLen > 0 && v.Len() > st.MaxLen {
+			return fmt.Errorf("length %d of option '%s' is greater than maxlen %d", v.Len(), st.Name, st.MaxLen)
+		}
+	}
+	return nil
+}
+
+// isAllowed returns whether or not a resolved string or integer value is one of the allowed values
+// of the option. Integers are compared by value so that i.e. 010 and 10 are the same.
+func (st StructTagOption) isAllowed(v reflect.Value) bool {
+	value := formatAllowed(v)
+	for _, allowed := range st.AllowedValues {
+		if a, err := convertToValue(allowed, v.Kind()); err == nil && formatAllowed(a) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// formatAllowed formats a string or integer value so that it can be compared to allowed values.
+func formatAllowed(v reflect.Value) string {
+	switch {
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10)
+	case v.CanUint():
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	return v.String()
+}
+
+// numericValue returns an integer or float value as a float64 and whether or not it is one.
+func numericValue(v reflect.Value) (float64, bool) {
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// ValidationErrors is returned by ValidateCache and contains an error for every cached type that
+// failed validation.
+type ValidationErrors []error
+
+// Error joins the message of every error on its own line.
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, err := range v {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors so that they can be checked with errors.Is and errors.As (on Go 1.20
+// and later).
+func (v ValidationErrors) Unwrap() []error {
+	return v
+}
+
+// Is reports whether any of the errors matches target so that errors.Is works before Go 1.20.
+func (v ValidationErrors) Is(target error) bool {
+	for _, err := range v {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches target so that errors.As works before Go 1.20.
+func (v ValidationErrors) As(target any) bool {
+	for _, err := range v {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRequired changes whether or not a struct tag option is required. Types that are already
+// cached are not validated again until ValidateCache is called. The name is matched the same way
+// as Lookup.
+func (t *StructTagCache[T]) SetRequired(name string, required bool) error {
+	name = t.optionName(name)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	opts, ok := t.structTagMap[name]
+	if !ok {
+		return fmt.Errorf("unknown struct tag option: %s", name)
+	}
+	// options are copied since Lookup may have returned them to other goroutines
+	opts = append([]StructTagOption(nil), opts...)
+	for i := range opts {
+		opts[i].Required = required
+	}
+	t.structTagMap[name] = opts
+	requiredTags := make([]string, 0, len(t.requiredTags))
+	for _, r := range t.requiredTags {
+		if r != name {
+			requiredTags = append(requiredTags, r)
+		}
+	}
+	if required {
+		requiredTags = append(requiredTags, name)
+	}
+	t.requiredTags = requiredTags
+	return nil
+}
+
+// ValidateCache parses the struct tags of every cached type again and returns ValidationErrors
+// for any that are no longer valid (i.e. after SetRequired). The cache itself is not changed.
+func (t *StructTagCache[T]) ValidateCache() error {
+	types := t.Types()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	sort.Slice(types, func(i, j int) bool {
+		return typeName(types[i]) < typeName(types[j])
+	})
+	errs := make(ValidationErrors, 0)
+	for _, rType := range types {
+		if _, err := t.parseType(rType); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", typeName(rType), err))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
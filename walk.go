@@ -0,0 +1,51 @@
+package spectagular
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrStopWalk can be returned by a Visitor to stop Walk early without it returning an error.
+var ErrStopWalk = errors.New("stop walk")
+
+// Visitor is used by Walk to visit the parsed struct tags of each field in a type. The index is
+// the index sequence of the field (as used by reflect.Value.FieldByIndex) so that fields of
+// embedded structs can be told apart from the fields of the outer struct.
+type Visitor[T any] interface {
+	VisitField(index []int, name string, value T) error
+}
+
+// Walk calls the visitor for the parsed struct tags of every field in a type (adding them to the
+// cache if needed) in the order the fields are declared. Fields of embedded structs are visited
+// in place of the field that embeds them. Walking stops at the first error returned by the
+// visitor, which is returned unless it is ErrStopWalk.
+func (t *StructTagCache[T]) Walk(rType reflect.Type, v Visitor[T]) error {
+	tags, err := t.GetOrAdd(rType)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err := v.VisitField(tag.FieldIndexPath, tag.FieldName, tag.Value); err != nil {
+			if errors.Is(err, ErrStopWalk) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Range calls fn with the parsed struct tags of every field in a type (adding them to the cache if
+// needed) in the order the fields are declared, stopping early if fn returns false.
+func (t *StructTagCache[T]) Range(rType reflect.Type, fn func(FieldTag[T]) bool) error {
+	tags, err := t.GetOrAdd(rType)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if !fn(tag) {
+			return nil
+		}
+	}
+	return nil
+}